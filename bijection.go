@@ -0,0 +1,61 @@
+package coordinate_supplier
+
+import "math/rand"
+
+// feistel is a keyed bijection over the indexes [0, n).
+// It is a balanced Feistel network over the smallest even number of bits that covers n.
+// Results outside [0, n) are cycle-walked: permuted again until they fall inside, which keeps the permutation a bijection.
+// Because the network covers less than 4*n values, an index takes fewer than 4 rounds of walking on average.
+type feistel struct {
+	n        uint64
+	halfBits uint
+	mask     uint64
+	keys     [4]uint64
+}
+
+// newFeistel returns a permutation of [0, n) keyed by seed, or keyed from the global math/rand source if seed is zero.
+func newFeistel(n uint64, seed int64) feistel {
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	f := feistel{n: n, halfBits: 1}
+	for f.halfBits < 32 && uint64(1)<<(2*f.halfBits) < n {
+		f.halfBits++
+	}
+	f.mask = uint64(1)<<f.halfBits - 1
+	state := uint64(seed)
+	for i := range f.keys {
+		state += 0x9E3779B97F4A7C15
+		f.keys[i] = mix64(state)
+	}
+	return f
+}
+
+// index returns the permuted position of i, which must be in [0, n).
+func (f feistel) index(i uint64) uint64 {
+	for {
+		i = f.round(i)
+		if i < f.n {
+			return i
+		}
+	}
+}
+
+// round permutes i over the whole domain of the network.
+func (f feistel) round(i uint64) uint64 {
+	left, right := i>>f.halfBits, i&f.mask
+	for _, key := range f.keys {
+		left, right = right, left^(mix64(right^key)&f.mask)
+	}
+	return left<<f.halfBits | right
+}
+
+// mix64 is the splitmix64 finalizer, scrambling the bits of v.
+func mix64(v uint64) uint64 {
+	v ^= v >> 30
+	v *= 0xBF58476D1CE4E5B9
+	v ^= v >> 27
+	v *= 0x94D049BB133111EB
+	v ^= v >> 31
+	return v
+}
@@ -12,22 +12,180 @@ type Coordinate struct {
 
 // MakeCoordinateList returns a slice of Coordinate, with each item representing one cell in the XY grid.
 // The Order determines the ordering of the coordinates in the slice.
+// Random orders are shuffled with the global math/rand source.
 func MakeCoordinateList(width, height int, order Order) (cs []Coordinate, err error) {
+	return makeCoordinateList(width, height, order, 0)
+}
+
+// makeCoordinateList is MakeCoordinateList with Random orders shuffled from seed, or the global math/rand source if seed is zero.
+func makeCoordinateList(width, height int, order Order, seed int64) (cs []Coordinate, err error) {
 	switch order {
 	case Asc:
 		cs = makeAscCoordinates(width, height)
 	case Random:
 		cs = makeAscCoordinates(width, height)
-		shuffleCoordinates(cs)
+		shuffleCoordinates(cs, seed)
+	case RandomBijection:
+		cs = make([]Coordinate, width*height)
+		permute := newFeistel(uint64(len(cs)), seed)
+		for i := range cs {
+			j := int(permute.index(uint64(i)))
+			cs[i] = Coordinate{X: j % width, Y: j / width}
+		}
 	case Desc:
 		cs = makeAscCoordinates(width, height)
 		reverseCoordinates(cs)
+	case Serpentine:
+		cs = makeSerpentineCoordinates(width, height)
+	case SerpentineDesc:
+		cs = makeSerpentineCoordinates(width, height)
+		reverseCoordinates(cs)
+	case ColumnSerpentine:
+		cs = makeSerpentineCoordinates(height, width)
+		transposeCoordinates(cs)
+	case ColumnSerpentineDesc:
+		cs = makeSerpentineCoordinates(height, width)
+		transposeCoordinates(cs)
+		reverseCoordinates(cs)
+	case SpiralIn:
+		cs = makeSpiralInCoordinates(width, height)
+	case SpiralOut:
+		cs = makeSpiralOutCoordinates(width, height)
+	case ColumnAsc:
+		cs = makeAscCoordinates(height, width)
+		transposeCoordinates(cs)
+	case ColumnDesc:
+		cs = makeAscCoordinates(height, width)
+		transposeCoordinates(cs)
+		reverseCoordinates(cs)
+	case Hilbert:
+		cs = makeHilbertCoordinates(width, height)
+	case ZOrder:
+		cs = makeZOrderCoordinates(width, height)
 	default:
 		err = fmt.Errorf("unknown order specified")
 	}
 	return
 }
 
+// MakeCoordinateListOptions returns a slice of Coordinate in the order a CoordinateSupplier created with opts hands them out.
+// Unlike MakeCoordinateList it also applies the MinX, MinY, Traversal, Seed, steps and Partition of the options.
+func MakeCoordinateListOptions(opts CoordinateSupplierOptions) ([]Coordinate, error) {
+	l, err := opts.layout()
+	if err != nil {
+		return nil, err
+	}
+	var cs []Coordinate
+	for _, t := range l.lattices {
+		latticeCoordinates, err := t.coordinates(opts.Order, opts.Traversal)
+		if err != nil {
+			return nil, err
+		}
+		if l.moved(t) {
+			for i, c := range latticeCoordinates {
+				latticeCoordinates[i] = l.place(t, c)
+			}
+		}
+		if len(l.lattices) == 1 {
+			cs = latticeCoordinates
+		} else {
+			cs = append(cs, latticeCoordinates...)
+		}
+	}
+	return l.selectCoordinates(cs), nil
+}
+
+// coordinateFunc returns a function that computes the Coordinate at an index of the order without materializing the list.
+// Only orders with a closed form are supported, ok is false for the others.
+func coordinateFunc(width, height int, order Order, seed int64) (at func(i int) Coordinate, ok bool) {
+	last := width*height - 1
+	asc := func(i int) Coordinate {
+		return Coordinate{X: i % width, Y: i / width}
+	}
+	serpentine := func(i int) Coordinate {
+		c := asc(i)
+		if c.Y%2 == 1 {
+			c.X = width - 1 - c.X
+		}
+		return c
+	}
+	// the column orders are their row counterparts on the transposed grid
+	columnAsc := func(i int) Coordinate {
+		return Coordinate{X: i / height, Y: i % height}
+	}
+	columnSerpentine := func(i int) Coordinate {
+		c := columnAsc(i)
+		if c.X%2 == 1 {
+			c.Y = height - 1 - c.Y
+		}
+		return c
+	}
+
+	switch order {
+	case Asc:
+		return asc, true
+	case Desc:
+		return func(i int) Coordinate { return asc(last - i) }, true
+	case Serpentine:
+		return serpentine, true
+	case SerpentineDesc:
+		return func(i int) Coordinate { return serpentine(last - i) }, true
+	case ColumnAsc:
+		return columnAsc, true
+	case ColumnDesc:
+		return func(i int) Coordinate { return columnAsc(last - i) }, true
+	case ColumnSerpentine:
+		return columnSerpentine, true
+	case ColumnSerpentineDesc:
+		return func(i int) Coordinate { return columnSerpentine(last - i) }, true
+	case RandomBijection:
+		permute := newFeistel(uint64(last+1), seed)
+		return func(i int) Coordinate { return asc(int(permute.index(uint64(i)))) }, true
+	default:
+		return nil, false
+	}
+}
+
+// coordinateFuncOptions is coordinateFunc with all options applied, like MakeCoordinateListOptions.
+func coordinateFuncOptions(opts CoordinateSupplierOptions) (func(i int) Coordinate, error) {
+	l, err := opts.layout()
+	if err != nil {
+		return nil, err
+	}
+	return l.coordinateFunc(opts.Order, opts.Traversal)
+}
+
+// makeEpochCoordinates returns the coordinates handed out during pass number epoch over the grid.
+// Random orders are shuffled with the seed of the epoch.
+func makeEpochCoordinates(opts CoordinateSupplierOptions, epoch uint64) ([]Coordinate, error) {
+	opts.Seed = epochSeed(opts.Seed, epoch)
+	return MakeCoordinateListOptions(opts)
+}
+
+// mustEpochCoordinates is makeEpochCoordinates for options that were validated by the constructor of a supplier, so it can not fail.
+func mustEpochCoordinates(opts CoordinateSupplierOptions, epoch uint64) []Coordinate {
+	coords, err := makeEpochCoordinates(opts, epoch)
+	if err != nil {
+		panic(err)
+	}
+	return coords
+}
+
+// mustEpochCoordinateFunc is like mustEpochCoordinates, but returns the function computing the coordinates of the pass.
+func mustEpochCoordinateFunc(opts CoordinateSupplierOptions, epoch uint64) func(i int) Coordinate {
+	opts.Seed = epochSeed(opts.Seed, epoch)
+	coordinateAt, err := coordinateFuncOptions(opts)
+	if err != nil {
+		panic(err)
+	}
+	return coordinateAt
+}
+
+// epochSeed derives the seed of pass number epoch over the grid from seed, epoch 0 uses seed itself.
+func epochSeed(seed int64, epoch uint64) int64 {
+	return seed + int64(epoch*0x9E3779B97F4A7C15)
+}
+
 func makeAscCoordinates(width, height int) []Coordinate {
 	coordinates := make([]Coordinate, 0, width*height)
 	var atX, atY int
@@ -49,6 +207,97 @@ func makeAscCoordinates(width, height int) []Coordinate {
 	return coordinates
 }
 
+// makeSerpentineCoordinates returns coordinates row by row, alternating direction:
+// row 0 left-to-right, row 1 right-to-left, and so on.
+func makeSerpentineCoordinates(width, height int) []Coordinate {
+	coordinates := makeAscCoordinates(width, height)
+	for row := 1; row < height; row += 2 {
+		reverseCoordinates(coordinates[row*width : (row+1)*width])
+	}
+	return coordinates
+}
+
+// makeSpiralInCoordinates returns coordinates winding counter-clockwise from the bottom left corner towards the center:
+// along the bottom row, up the right column, back along the top row and down the left column, then the next ring inwards.
+func makeSpiralInCoordinates(width, height int) []Coordinate {
+	coordinates := make([]Coordinate, 0, width*height)
+	left, right, bottom, top := 0, width-1, 0, height-1
+	for left <= right && bottom <= top {
+		for x := left; x <= right; x++ {
+			coordinates = append(coordinates, Coordinate{X: x, Y: bottom})
+		}
+		for y := bottom + 1; y <= top; y++ {
+			coordinates = append(coordinates, Coordinate{X: right, Y: y})
+		}
+		if bottom < top {
+			for x := right - 1; x >= left; x-- {
+				coordinates = append(coordinates, Coordinate{X: x, Y: top})
+			}
+		}
+		if left < right {
+			for y := top - 1; y > bottom; y-- {
+				coordinates = append(coordinates, Coordinate{X: left, Y: y})
+			}
+		}
+		left++
+		right--
+		bottom++
+		top--
+	}
+	return coordinates
+}
+
+// makeSpiralOutCoordinates returns coordinates winding clockwise from the center cell outwards,
+// with runs of 1, 1, 2, 2, 3, 3, ... cells, skipping the cells outside the grid.
+// With an even size the center cell is the one left of the middle of the columns, and above the middle of the rows,
+// and the first run goes right instead of left, so on square grids this is makeSpiralInCoordinates reversed.
+func makeSpiralOutCoordinates(width, height int) []Coordinate {
+	coordinates := make([]Coordinate, 0, width*height)
+	x, y := (width-1)/2, height/2
+	coordinates = append(coordinates, Coordinate{X: x, Y: y})
+	// left, up, right, down
+	directions := [4][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}}
+	turn := 0
+	if width%2 == 0 {
+		turn = 2
+	}
+	for run := 0; len(coordinates) < width*height; run++ {
+		dx, dy := directions[(turn+run)%4][0], directions[(turn+run)%4][1]
+		length := run/2 + 1
+		// only the steps of the run that are inside the grid, each run is a straight line
+		first, last := 1, length
+		if dx != 0 {
+			if y < 0 || y >= height {
+				first = length + 1
+			} else if dx > 0 {
+				first, last = maxInt(first, -x), minInt(last, width-1-x)
+			} else {
+				first, last = maxInt(first, x-(width-1)), minInt(last, x)
+			}
+		} else {
+			if x < 0 || x >= width {
+				first = length + 1
+			} else if dy > 0 {
+				first, last = maxInt(first, -y), minInt(last, height-1-y)
+			} else {
+				first, last = maxInt(first, y-(height-1)), minInt(last, y)
+			}
+		}
+		for step := first; step <= last; step++ {
+			coordinates = append(coordinates, Coordinate{X: x + dx*step, Y: y + dy*step})
+		}
+		x, y = x+dx*length, y+dy*length
+	}
+	return coordinates
+}
+
+// transposeCoordinates swaps X and Y of every coordinate, turning a row-major order into a column-major order.
+func transposeCoordinates(cs []Coordinate) {
+	for i := range cs {
+		cs[i].X, cs[i].Y = cs[i].Y, cs[i].X
+	}
+}
+
 func reverseCoordinates(cs []Coordinate) {
 	i := 0
 	j := len(cs) - 1
@@ -59,6 +308,11 @@ func reverseCoordinates(cs []Coordinate) {
 	}
 }
 
-func shuffleCoordinates(cs []Coordinate) {
-	rand.Shuffle(len(cs), func(i, j int) { cs[i], cs[j] = cs[j], cs[i] })
+func shuffleCoordinates(cs []Coordinate, seed int64) {
+	swap := func(i, j int) { cs[i], cs[j] = cs[j], cs[i] }
+	if seed == 0 {
+		rand.Shuffle(len(cs), swap)
+		return
+	}
+	rand.New(rand.NewSource(seed)).Shuffle(len(cs), swap)
 }
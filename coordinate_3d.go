@@ -0,0 +1,162 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Coordinate3D is one cell in a XYZ grid.
+type Coordinate3D struct {
+	X int
+	Y int
+	Z int
+}
+
+// CoordinateSupplier3D provides XYZ coordinates in a XYZ grid, like CoordinateSupplier does in a XY grid.
+type CoordinateSupplier3D interface {
+	// Next should be called repeatedly to iterate through each triple of coordinates.
+	// If done is false, the returned coordinates should be used, they are valid.
+	// If done is true, the returned coordinates should be discarded and Next should not be called any longer.
+	Next() (x, y, z int, done bool)
+}
+
+// CoordinateSupplierOptions3D control the way coordinates are handed out in a XYZ grid.
+type CoordinateSupplierOptions3D struct {
+	Width  int   // width of Coordinate3D grid
+	Height int   // height of Coordinate3D grid
+	Depth  int   // depth of Coordinate3D grid
+	Order  Order // order that coordinates will be handed out (Asc, Desc, Random, RandomBijection, Hilbert or ZOrder)
+	Repeat bool  // if each Coordinate3D should be handed out exactly once, or if iterating should loop through indefinitely
+	Seed   int64 // seed for Random orders, the same seed always hands out the same permutation. Zero picks a random one
+}
+
+type coordinateSupplier3D struct {
+	sequence sequence[Coordinate3D]
+}
+
+// NewCoordinateSupplier3D returns a CoordinateSupplier3D synchronized with atomic.AddUint64, like NewCoordinateSupplierAtomic.
+// Ascending order hands out X first, then Y, then Z. The whole volume is one sequence, so Random shuffles across all Z slices.
+// It also implements Close and NextContext, like a ClosableCoordinateSupplier.
+func NewCoordinateSupplier3D(opts CoordinateSupplierOptions3D) (CoordinateSupplier3D, error) {
+	coords, err := MakeCoordinateList3D(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed make coordinate list: %w", err)
+	}
+	return &coordinateSupplier3D{sequence: sequence[Coordinate3D]{items: coords, total: uint64(len(coords)), repeat: opts.Repeat}}, nil
+}
+
+// MakeCoordinateList3D returns a slice of Coordinate3D, with each item representing one cell in the XYZ grid,
+// in the order a CoordinateSupplier3D created with opts hands them out.
+func MakeCoordinateList3D(opts CoordinateSupplierOptions3D) ([]Coordinate3D, error) {
+	if opts.Width < 1 {
+		return nil, fmt.Errorf("minimum width is 1")
+	}
+	if opts.Height < 1 {
+		return nil, fmt.Errorf("minimum height is 1")
+	}
+	if opts.Depth < 1 {
+		return nil, fmt.Errorf("minimum depth is 1")
+	}
+	// the same limit on the number of cells as NewCoordinateSupplierND
+	shape := []int{opts.Width, opts.Height, opts.Depth}
+	if err := validateShape(shape); err != nil {
+		return nil, err
+	}
+	indexes, err := makeIndexOrder(shape, opts.Order, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+	cs := make([]Coordinate3D, len(indexes))
+	for i, index := range indexes {
+		cs[i] = Coordinate3D{
+			X: index % opts.Width,
+			Y: index / opts.Width % opts.Height,
+			Z: index / (opts.Width * opts.Height),
+		}
+	}
+	return cs, nil
+}
+
+// Next returns the next coordinate to be supplied.
+// It may be possible to receive some coordinates slightly out of order when called concurrently.
+func (c *coordinateSupplier3D) Next() (x, y, z int, done bool) {
+	coordinate, done := c.sequence.next()
+	return coordinate.X, coordinate.Y, coordinate.Z, done
+}
+
+// NextContext returns the next coordinate to be supplied, or closes the supplier if ctx is done.
+func (c *coordinateSupplier3D) NextContext(ctx context.Context) (x, y, z int, done bool) {
+	coordinate, done := c.sequence.nextContext(ctx)
+	return coordinate.X, coordinate.Y, coordinate.Z, done
+}
+
+// Close marks the supplier as done for all callers.
+func (c *coordinateSupplier3D) Close() error {
+	c.sequence.close()
+	return nil
+}
+
+// makeIndexOrder returns the indexes of all cells of a grid with the given size along each axis, in the order they are handed out.
+// Cells are indexed in ascending order, the first axis varying fastest.
+// Random orders are shuffled from seed, or a random seed if it is zero.
+// With two axes every order is supported, and the cells are handed out in the same order as by MakeCoordinateList.
+func makeIndexOrder(shape []int, order Order, seed int64) ([]int, error) {
+	if order == Random {
+		for seed == 0 {
+			seed = rand.Int63()
+		}
+	}
+	if len(shape) == 2 {
+		coords, err := makeCoordinateList(shape[0], shape[1], order, seed)
+		if err != nil {
+			return nil, err
+		}
+		indexes := make([]int, len(coords))
+		for i, c := range coords {
+			indexes[i] = c.X + c.Y*shape[0]
+		}
+		return indexes, nil
+	}
+
+	cells := 1
+	for _, size := range shape {
+		cells *= size
+	}
+	asc := func() []int {
+		indexes := make([]int, cells)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+
+	switch order {
+	case Asc:
+		return asc(), nil
+	case Desc:
+		indexes := asc()
+		slices.Reverse(indexes)
+		return indexes, nil
+	case Random:
+		indexes := asc()
+		rand.New(rand.NewSource(seed)).Shuffle(len(indexes), func(i, j int) {
+			indexes[i], indexes[j] = indexes[j], indexes[i]
+		})
+		return indexes, nil
+	case RandomBijection:
+		permute := newFeistel(uint64(cells), seed)
+		indexes := make([]int, cells)
+		for i := range indexes {
+			indexes[i] = int(permute.index(uint64(i)))
+		}
+		return indexes, nil
+	case Hilbert:
+		return makeCurveIndexes(shape, cells, hilbertKey), nil
+	case ZOrder:
+		return makeCurveIndexes(shape, cells, mortonKey), nil
+	default:
+		return nil, fmt.Errorf("order %s is not supported with %d dimensions", OrderToString(order), len(shape))
+	}
+}
@@ -0,0 +1,163 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+var orders3DToTest = []Order{Asc, Desc, Random, RandomBijection, Hilbert, ZOrder}
+
+func Test_Coordinate_Supplier_3D_EachCoordinateOnce(t *testing.T) {
+	for _, order := range orders3DToTest {
+		for _, size := range [][3]int{{1, 1, 1}, {4, 4, 4}, {5, 3, 2}, {1, 7, 3}, {6, 1, 1}} {
+			t.Run(fmt.Sprintf("%s-%dx%dx%d", OrderToString(order), size[0], size[1], size[2]), func(t *testing.T) {
+				cs, err := NewCoordinateSupplier3D(CoordinateSupplierOptions3D{Width: size[0], Height: size[1], Depth: size[2], Order: order})
+				require.NoError(t, err)
+				requireEachCoordinate3DOnce(t, size[0], size[1], size[2], consumeAll3D(cs))
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_3D_Asc(t *testing.T) {
+	cs, err := NewCoordinateSupplier3D(CoordinateSupplierOptions3D{Width: 2, Height: 2, Depth: 2, Order: Asc})
+	require.NoError(t, err)
+	want := []Coordinate3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}, {0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1}}
+	require.Equal(t, want, consumeAll3D(cs))
+
+	cs, err = NewCoordinateSupplier3D(CoordinateSupplierOptions3D{Width: 2, Height: 2, Depth: 2, Order: Desc})
+	require.NoError(t, err)
+	got := consumeAll3D(cs)
+	for i := range got {
+		require.Equal(t, want[len(want)-1-i], got[i])
+	}
+}
+
+func Test_Coordinate_Supplier_3D_Hilbert_Adjacent(t *testing.T) {
+	for _, size := range []int{2, 4, 8} {
+		coords, err := MakeCoordinateList3D(CoordinateSupplierOptions3D{Width: size, Height: size, Depth: size, Order: Hilbert})
+		require.NoError(t, err)
+		require.Equal(t, Coordinate3D{}, coords[0])
+		for i := 1; i < len(coords); i++ {
+			a, b := coords[i-1], coords[i]
+			require.Equal(t, 1, abs(a.X-b.X)+abs(a.Y-b.Y)+abs(a.Z-b.Z), "%v and %v are not adjacent", a, b)
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_3D_ZOrder(t *testing.T) {
+	coords, err := MakeCoordinateList3D(CoordinateSupplierOptions3D{Width: 2, Height: 2, Depth: 2, Order: ZOrder})
+	require.NoError(t, err)
+	require.Equal(t, []Coordinate3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}, {0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1}}, coords)
+
+	// a single slice is the XY Z-order
+	coords, err = MakeCoordinateList3D(CoordinateSupplierOptions3D{Width: 7, Height: 5, Depth: 1, Order: ZOrder})
+	require.NoError(t, err)
+	want, err := MakeCoordinateList(7, 5, ZOrder)
+	require.NoError(t, err)
+	for i := range want {
+		require.Equal(t, Coordinate3D{X: want[i].X, Y: want[i].Y}, coords[i])
+	}
+}
+
+func Test_Coordinate_Supplier_3D_Random_Seed(t *testing.T) {
+	opts := CoordinateSupplierOptions3D{Width: 6, Height: 5, Depth: 4, Order: Random, Seed: 42}
+	first, err := MakeCoordinateList3D(opts)
+	require.NoError(t, err)
+	second, err := MakeCoordinateList3D(opts)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+
+	// the permutation spans all slices, instead of shuffling one slice after the other
+	slices := make(map[int]bool)
+	for _, c := range first[:opts.Width*opts.Height] {
+		slices[c.Z] = true
+	}
+	require.Greater(t, len(slices), 1)
+}
+
+func Test_Coordinate_Supplier_3D_Repeat(t *testing.T) {
+	cs, err := NewCoordinateSupplier3D(CoordinateSupplierOptions3D{Width: 3, Height: 2, Depth: 2, Order: Hilbert, Repeat: true})
+	require.NoError(t, err)
+	want, err := MakeCoordinateList3D(CoordinateSupplierOptions3D{Width: 3, Height: 2, Depth: 2, Order: Hilbert})
+	require.NoError(t, err)
+	for pass := 0; pass < 3; pass++ {
+		for _, c := range want {
+			x, y, z, done := cs.Next()
+			require.False(t, done)
+			require.Equal(t, c, Coordinate3D{x, y, z})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_3D_Concurrent(t *testing.T) {
+	cs, err := NewCoordinateSupplier3D(CoordinateSupplierOptions3D{Width: 30, Height: 20, Depth: 10, Order: Random})
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var coords []Coordinate3D
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got := consumeAll3D(cs)
+			mu.Lock()
+			coords = append(coords, got...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	requireEachCoordinate3DOnce(t, 30, 20, 10, coords)
+}
+
+func Test_Coordinate_Supplier_3D_Close(t *testing.T) {
+	cs, err := NewCoordinateSupplier3D(CoordinateSupplierOptions3D{Width: 3, Height: 3, Depth: 3, Order: Asc, Repeat: true})
+	require.NoError(t, err)
+	ccs := cs.(interface {
+		NextContext(ctx context.Context) (x, y, z int, done bool)
+		Close() error
+	})
+	_, _, _, done := ccs.NextContext(context.Background())
+	require.False(t, done)
+	require.NoError(t, ccs.Close())
+	_, _, _, done = cs.Next()
+	require.True(t, done)
+}
+
+func Test_Coordinate_Supplier_3D_Invalid(t *testing.T) {
+	optsToTest := []CoordinateSupplierOptions3D{
+		{Width: 0, Height: 1, Depth: 1, Order: Asc},
+		{Width: 1, Height: 0, Depth: 1, Order: Asc},
+		{Width: 1, Height: 1, Depth: 0, Order: Asc},
+		{Width: 2, Height: 2, Depth: 2, Order: Serpentine},
+		{Width: 1 << 20, Height: 1 << 22, Depth: 1, Order: Asc},
+		{Width: 1 << 11, Height: 1 << 11, Depth: 1 << 11, Order: Asc},
+	}
+	for _, opts := range optsToTest {
+		_, err := NewCoordinateSupplier3D(opts)
+		require.Error(t, err)
+	}
+}
+
+// consumeAll3D returns every coordinate handed out by a non-repeating CoordinateSupplier3D.
+func consumeAll3D(cs CoordinateSupplier3D) []Coordinate3D {
+	var coords []Coordinate3D
+	for x, y, z, done := cs.Next(); !done; x, y, z, done = cs.Next() {
+		coords = append(coords, Coordinate3D{x, y, z})
+	}
+	return coords
+}
+
+// requireEachCoordinate3DOnce checks that coords holds every cell of the width x height x depth grid exactly once.
+func requireEachCoordinate3DOnce(t testing.TB, width, height, depth int, coords []Coordinate3D) {
+	require.Len(t, coords, width*height*depth)
+	seen := make(map[Coordinate3D]bool, len(coords))
+	for _, c := range coords {
+		require.True(t, c.X >= 0 && c.X < width && c.Y >= 0 && c.Y < height && c.Z >= 0 && c.Z < depth, "coordinate %v outside grid", c)
+		require.False(t, seen[c], "coordinate %v handed out twice", c)
+		seen[c] = true
+	}
+}
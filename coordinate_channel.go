@@ -0,0 +1,27 @@
+package coordinate_supplier
+
+import "context"
+
+// CoordinateChannel starts a goroutine that drains cs into the returned channel, buffered with bufferSize coordinates.
+// The channel is closed when cs is done or ctx is cancelled, so it can be consumed with `for c := range ch`.
+// A consumer that stops reading before the channel is closed must cancel ctx, otherwise the goroutine blocks forever.
+// Once ctx is cancelled no more coordinates are taken from cs, so a shared cs is not drained by a channel nobody reads.
+// A negative bufferSize is treated as 0, an unbuffered channel.
+func CoordinateChannel(ctx context.Context, cs CoordinateSupplier, bufferSize int) <-chan Coordinate {
+	ch := make(chan Coordinate, maxInt(bufferSize, 0))
+	go func() {
+		defer close(ch)
+		for ctx.Err() == nil {
+			x, y, done := cs.Next()
+			if done {
+				return
+			}
+			select {
+			case ch <- Coordinate{X: x, Y: y}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,248 @@
+package coordinate_supplier
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// checkpointVersion is the version of the Checkpoint encoding written by this package.
+// It is increased whenever the encoding changes, older versions can still be read.
+const checkpointVersion = 5
+
+// Checkpoint is the state of a supplier: its options and its position.
+// It can be persisted with MarshalBinary or as JSON, and resumed by a new process with NewCoordinateSupplierFromCheckpoint,
+// to continue handing out coordinates exactly where the old supplier stopped.
+type Checkpoint struct {
+	Version int                       `json:"version"` // version of the encoding
+	Options CoordinateSupplierOptions `json:"options"` // options of the supplier, with the Seed it actually used
+	Index   uint64                    `json:"index"`   // position in the current pass over the grid
+	Epoch   uint64                    `json:"epoch"`   // number of completed passes over the grid
+	Lazy    bool                      `json:"lazy"`    // taken from a supplier of NewCoordinateSupplierLazy, which does not materialize the grid
+}
+
+// CheckpointCoordinateSupplier is a CoordinateSupplier that can save and restore its state.
+// The encodings of a supplier are the encodings of its Checkpoint.
+// The suppliers of NewCoordinateSupplier, NewCoordinateSupplierAtomic, NewCoordinateSupplierRWMutex, NewCoordinateSupplierOrdered
+// and NewCoordinateSupplierLazy implement it. The workers of NewCoordinateSupplierSharded and CompletionTracker.Filter do not.
+type CheckpointCoordinateSupplier interface {
+	CoordinateSupplier
+	// Checkpoint returns the current state of the supplier.
+	Checkpoint() Checkpoint
+	// Restore replaces the state of the supplier with cp. It must not be called concurrently with other methods.
+	Restore(cp Checkpoint) error
+
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary(data []byte) error
+	MarshalJSON() ([]byte, error)
+	UnmarshalJSON(data []byte) error
+}
+
+// ErrCheckpointVersion is returned when decoding a Checkpoint written by a newer version of this package.
+var ErrCheckpointVersion = errors.New("unsupported checkpoint version")
+
+// checkpointCodec implements the encodings of a CheckpointCoordinateSupplier on its Checkpoint and Restore methods.
+// Suppliers embed it, pointing at themselves.
+type checkpointCodec struct {
+	supplier interface {
+		Checkpoint() Checkpoint
+		Restore(cp Checkpoint) error
+	}
+}
+
+// MarshalBinary encodes the Checkpoint of the supplier.
+func (e checkpointCodec) MarshalBinary() ([]byte, error) {
+	return e.supplier.Checkpoint().MarshalBinary()
+}
+
+// UnmarshalBinary restores the supplier from a Checkpoint encoded with MarshalBinary.
+func (e checkpointCodec) UnmarshalBinary(data []byte) error {
+	var cp Checkpoint
+	if err := cp.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return e.supplier.Restore(cp)
+}
+
+// MarshalJSON encodes the Checkpoint of the supplier as JSON.
+func (e checkpointCodec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.supplier.Checkpoint())
+}
+
+// UnmarshalJSON restores the supplier from a Checkpoint encoded as JSON.
+func (e checkpointCodec) UnmarshalJSON(data []byte) error {
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return err
+	}
+	return e.supplier.Restore(cp)
+}
+
+// NewCoordinateSupplierFromCheckpoint returns the default CoordinateSupplier implementation, resumed from cp.
+// A checkpoint taken from a supplier of NewCoordinateSupplierLazy is resumed by a lazy supplier again,
+// so grids too large to materialize can be resumed as well.
+func NewCoordinateSupplierFromCheckpoint(cp Checkpoint) (CoordinateSupplier, error) {
+	var cs CheckpointCoordinateSupplier = &coordinateSupplierAtomic{}
+	if cp.Lazy {
+		cs = &coordinateSupplierLazy{}
+	}
+	if err := cs.Restore(cp); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// newCheckpoint returns the checkpoint of a supplier with the options that handed out handedOut of total coordinates per pass.
+func newCheckpoint(opts CoordinateSupplierOptions, handedOut, total uint64) Checkpoint {
+	cp := Checkpoint{
+		Version: checkpointVersion,
+		Options: opts,
+		Index:   handedOut % total,
+		Epoch:   handedOut / total,
+	}
+	if !opts.Repeat && handedOut >= total {
+		// keep the end of the only pass, instead of the start of one that never comes
+		cp.Index, cp.Epoch = total, 0
+	}
+	return cp
+}
+
+// position returns the index of the next coordinate to hand out counted over all passes, as used by Seek.
+func (cp Checkpoint) position(total uint64) uint64 {
+	return cp.Epoch*total + cp.Index
+}
+
+// MarshalBinary encodes the checkpoint into a compact binary form.
+func (cp Checkpoint) MarshalBinary() ([]byte, error) {
+	data := []byte{checkpointVersion}
+	o := cp.Options
+	data = binary.AppendUvarint(data, uint64(o.Width))
+	data = binary.AppendUvarint(data, uint64(o.Height))
+	data = binary.AppendUvarint(data, uint64(o.Order))
+	data = append(data, flags(o.Repeat, o.Traversal.ColumnMajor, o.Traversal.ReverseX, o.Traversal.ReverseY, o.Reshuffle, o.Progressive, cp.Lazy))
+	data = binary.AppendVarint(data, o.Seed)
+	data = binary.AppendUvarint(data, cp.Index)
+	data = binary.AppendUvarint(data, cp.Epoch)
+	// version 2
+	data = binary.AppendUvarint(data, uint64(o.Partition.Count))
+	data = binary.AppendUvarint(data, uint64(o.Partition.Index))
+	data = binary.AppendUvarint(data, uint64(o.Partition.Partitioning))
+	// version 3
+	data = binary.AppendVarint(data, int64(o.MinX))
+	data = binary.AppendVarint(data, int64(o.MinY))
+	// version 4
+	data = binary.AppendUvarint(data, uint64(o.StepX))
+	data = binary.AppendUvarint(data, uint64(o.StepY))
+	return data, nil
+}
+
+// UnmarshalBinary decodes a checkpoint encoded by MarshalBinary.
+func (cp *Checkpoint) UnmarshalBinary(data []byte) error {
+	d := checkpointDecoder{data: data}
+	version := int(d.byte())
+	if d.err == nil && (version < 1 || version > checkpointVersion) {
+		return fmt.Errorf("%w: %d", ErrCheckpointVersion, version)
+	}
+	var o CoordinateSupplierOptions
+	o.Width = int(d.uvarint())
+	o.Height = int(d.uvarint())
+	o.Order = Order(d.uvarint())
+	f := d.byte()
+	o.Repeat, o.Traversal.ColumnMajor, o.Traversal.ReverseX, o.Traversal.ReverseY, o.Reshuffle = f&1 > 0, f&2 > 0, f&4 > 0, f&8 > 0, f&16 > 0
+	o.Progressive = f&32 > 0 // version 4
+	lazy := f&64 > 0         // version 5
+	o.Seed = d.varint()
+	index := d.uvarint()
+	epoch := d.uvarint()
+	if version >= 2 {
+		o.Partition.Count = int(d.uvarint())
+		o.Partition.Index = int(d.uvarint())
+		o.Partition.Partitioning = Partitioning(d.uvarint())
+	}
+	if version >= 3 {
+		o.MinX = int(d.varint())
+		o.MinY = int(d.varint())
+	}
+	if version >= 4 {
+		o.StepX = int(d.uvarint())
+		o.StepY = int(d.uvarint())
+	}
+	if d.err != nil {
+		return fmt.Errorf("failed decode checkpoint: %w", d.err)
+	}
+
+	*cp = Checkpoint{Version: version, Options: o, Index: index, Epoch: epoch, Lazy: lazy}
+	return nil
+}
+
+// UnmarshalJSON decodes a checkpoint from JSON, rejecting versions newer than this package.
+func (cp *Checkpoint) UnmarshalJSON(data []byte) error {
+	// checkpointJSON has the same fields, but not this method
+	type checkpointJSON Checkpoint
+	var decoded checkpointJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Version < 1 || decoded.Version > checkpointVersion {
+		return fmt.Errorf("%w: %d", ErrCheckpointVersion, decoded.Version)
+	}
+	*cp = Checkpoint(decoded)
+	return nil
+}
+
+// flags packs bools into the bits of a byte, the first one in the lowest bit.
+func flags(bs ...bool) byte {
+	var f byte
+	for i, b := range bs {
+		if b {
+			f |= 1 << i
+		}
+	}
+	return f
+}
+
+// checkpointDecoder reads the fields of an encoded checkpoint, remembering the first error.
+type checkpointDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *checkpointDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) < 1 {
+		d.err = errors.New("unexpected end of data")
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *checkpointDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errors.New("invalid uvarint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *checkpointDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = errors.New("invalid varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
@@ -0,0 +1,152 @@
+package coordinate_supplier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCheckpoint_Resume(t *testing.T) {
+	optsToTest := []CoordinateSupplierOptions{
+		{Width: 7, Height: 5, Order: Desc, Traversal: Traversal{ColumnMajor: true, ReverseY: true}},
+		{Width: 7, Height: 5, Order: Asc, Repeat: true},
+		{Width: 7, Height: 5, Order: RandomBijection, Repeat: true, Reshuffle: true},
+		{Width: 7, Height: 5, Order: RandomBijection, Seed: -3},
+		{Width: 7, Height: 5, Order: Asc, Repeat: true, Partition: PartitionOptions{Count: 3, Index: 1, Partitioning: PartitionTiles}},
+		{Width: 7, Height: 5, MinX: -3, MinY: -2, Order: Serpentine},
+		{Width: 7, Height: 5, Order: RandomBijection, Repeat: true, Reshuffle: true, StepX: 4, StepY: 2, Progressive: true},
+	}
+	for _, testOpts := range optsToTest {
+		for _, consumed := range []int{0, 1, 20, 35, 80} {
+			for _, supplier := range suppliersToBenchmark {
+				t.Run(fmt.Sprintf("%s-%s-repeat%t-consumed%d", supplier.name, OrderToString(testOpts.Order), testOpts.Repeat, consumed), func(t *testing.T) {
+					cs, err := supplier.new(testOpts)
+					require.NoError(t, err)
+					for i := 0; i < consumed; i++ {
+						cs.Next()
+					}
+					ccs, ok := cs.(CheckpointCoordinateSupplier)
+					require.True(t, ok)
+					binaryData, err := ccs.MarshalBinary()
+					require.NoError(t, err)
+					jsonData, err := json.Marshal(ccs)
+					require.NoError(t, err)
+					want := consumeUpTo(cs, 100)
+
+					// restore into a supplier of the same implementation with other options
+					restored, err := supplier.new(CoordinateSupplierOptions{Width: 2, Height: 2, Order: Asc})
+					require.NoError(t, err)
+					require.NoError(t, restored.(CheckpointCoordinateSupplier).UnmarshalBinary(binaryData))
+					require.Equal(t, want, consumeUpTo(restored, 100))
+
+					restored, err = supplier.new(CoordinateSupplierOptions{Width: 2, Height: 2, Order: Asc})
+					require.NoError(t, err)
+					require.NoError(t, json.Unmarshal(jsonData, restored))
+					require.Equal(t, want, consumeUpTo(restored, 100))
+
+					// resume with the default implementation
+					var cp Checkpoint
+					require.NoError(t, cp.UnmarshalBinary(binaryData))
+					restored, err = NewCoordinateSupplierFromCheckpoint(cp)
+					require.NoError(t, err)
+					require.Equal(t, want, consumeUpTo(restored, 100))
+				})
+			}
+		}
+	}
+}
+
+func TestCheckpoint_Random_Unseeded(t *testing.T) {
+	// the seed drawn for an unseeded Random order is part of the checkpoint
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 10, Height: 10, Order: Random})
+	require.NoError(t, err)
+	cs.Next()
+	cp := cs.(CheckpointCoordinateSupplier).Checkpoint()
+	require.NotZero(t, cp.Options.Seed)
+	require.Equal(t, uint64(1), cp.Index)
+
+	restored, err := NewCoordinateSupplierFromCheckpoint(cp)
+	require.NoError(t, err)
+	require.Equal(t, consumeAll(t, cs), consumeAll(t, restored))
+}
+
+func TestCheckpoint_Lazy_Huge_Grid(t *testing.T) {
+	// the grid is too large to materialize, so it is resumed by a lazy supplier again
+	testOpts := CoordinateSupplierOptions{Width: 100000, Height: 100000, Order: RandomBijection, Seed: 5}
+	cs, err := NewCoordinateSupplierLazy(testOpts)
+	require.NoError(t, err)
+	consumeUpTo(cs, 10)
+	data, err := cs.(CheckpointCoordinateSupplier).MarshalBinary()
+	require.NoError(t, err)
+	want := consumeUpTo(cs, 10)
+
+	var cp Checkpoint
+	require.NoError(t, cp.UnmarshalBinary(data))
+	require.True(t, cp.Lazy)
+	restored, err := NewCoordinateSupplierFromCheckpoint(cp)
+	require.NoError(t, err)
+	require.IsType(t, &coordinateSupplierLazy{}, restored)
+	require.Equal(t, want, consumeUpTo(restored, 10))
+}
+
+func TestCheckpoint_Encoding(t *testing.T) {
+	cp := Checkpoint{
+		Version: checkpointVersion,
+		Options: CoordinateSupplierOptions{Width: 300, Height: 2, Order: Hilbert, Repeat: true, Traversal: Traversal{ReverseX: true}, Seed: -12345, Reshuffle: true, Partition: PartitionOptions{Count: 4, Index: 3, Partitioning: PartitionContiguous}, MinX: -500, MinY: 200, StepX: 4, StepY: 2, Progressive: true},
+		Index:   17,
+		Epoch:   1 << 40,
+		Lazy:    true,
+	}
+	data, err := cp.MarshalBinary()
+	require.NoError(t, err)
+	var decoded Checkpoint
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, cp, decoded)
+
+	// truncated data
+	for i := 0; i < len(data); i++ {
+		require.Error(t, decoded.UnmarshalBinary(data[:i]))
+	}
+
+	// older versions end before the fields added later, which are one byte each when zero
+	older := cp
+	older.Options.Partition = PartitionOptions{}
+	older.Options.MinX, older.Options.MinY = 0, 0
+	older.Options.StepX, older.Options.StepY, older.Options.Progressive = 0, 0, false
+	older.Lazy = false
+	olderData, err := older.MarshalBinary()
+	require.NoError(t, err)
+	for version, end := range map[int]int{1: len(olderData) - 7, 2: len(olderData) - 4, 3: len(olderData) - 2, 4: len(olderData)} {
+		older.Version = version
+		require.NoError(t, decoded.UnmarshalBinary(append([]byte{byte(version)}, olderData[1:end]...)))
+		require.Equal(t, older, decoded)
+	}
+
+	// newer versions are rejected
+	data[0] = checkpointVersion + 1
+	require.True(t, errors.Is(decoded.UnmarshalBinary(data), ErrCheckpointVersion))
+
+	jsonData, err := json.Marshal(cp)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(jsonData, &decoded))
+	require.Equal(t, cp, decoded)
+
+	cp.Version = checkpointVersion + 1
+	jsonData, err = json.Marshal(cp)
+	require.NoError(t, err)
+	require.True(t, errors.Is(json.Unmarshal(jsonData, &decoded), ErrCheckpointVersion))
+}
+
+// consumeUpTo returns up to n coordinates handed out by cs, fewer if it is done.
+func consumeUpTo(cs CoordinateSupplier, n int) []Coordinate {
+	var coords []Coordinate
+	for x, y, done := cs.Next(); !done; x, y, done = cs.Next() {
+		coords = append(coords, Coordinate{x, y})
+		if len(coords) == n {
+			break
+		}
+	}
+	return coords
+}
@@ -0,0 +1,229 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math/bits"
+	"sync/atomic"
+)
+
+// CompletionTracker records which coordinates of a grid are completed, in a bitset with one bit per coordinate.
+// Completing a coordinate is a single atomic operation, so it is cheap to call from many concurrent consumers.
+// Combined with Filter, it lets a partially failed sweep be re-run for only the coordinates that were not completed.
+type CompletionTracker struct {
+	minX   int
+	minY   int
+	width  int
+	height int
+	bits   []uint64
+	done   uint64
+}
+
+// NewCompletionTracker returns a CompletionTracker for the grid of opts, with no coordinates completed.
+func NewCompletionTracker(opts CoordinateSupplierOptions) (*CompletionTracker, error) {
+	if opts.Width < 1 {
+		return nil, fmt.Errorf("minimum width is 1")
+	}
+	if opts.Height < 1 {
+		return nil, fmt.Errorf("minimum height is 1")
+	}
+	return &CompletionTracker{
+		minX:   opts.MinX,
+		minY:   opts.MinY,
+		width:  opts.Width,
+		height: opts.Height,
+		bits:   make([]uint64, (opts.Width*opts.Height+63)/64),
+	}, nil
+}
+
+// Complete marks the coordinate as completed, and reports whether it was not completed before.
+// The coordinate must be inside the grid.
+func (t *CompletionTracker) Complete(x, y int) bool {
+	word, mask := t.bit(x, y)
+	if atomic.OrUint64(&t.bits[word], mask)&mask != 0 {
+		return false
+	}
+	atomic.AddUint64(&t.done, 1)
+	return true
+}
+
+// IsDone reports whether the coordinate is completed. The coordinate must be inside the grid.
+func (t *CompletionTracker) IsDone(x, y int) bool {
+	word, mask := t.bit(x, y)
+	return atomic.LoadUint64(&t.bits[word])&mask != 0
+}
+
+// CountDone returns the number of completed coordinates.
+func (t *CompletionTracker) CountDone() int {
+	return int(atomic.LoadUint64(&t.done))
+}
+
+// FirstMissing returns the first coordinate in Asc order that is not completed.
+// If ok is false, all coordinates are completed.
+func (t *CompletionTracker) FirstMissing() (c Coordinate, ok bool) {
+	for c := range t.Missing() {
+		return c, true
+	}
+	return Coordinate{}, false
+}
+
+// Missing returns an iterator over the coordinates that are not completed, in Asc order.
+// Coordinates completed concurrently while iterating may or may not be skipped.
+func (t *CompletionTracker) Missing() iter.Seq[Coordinate] {
+	return func(yield func(Coordinate) bool) {
+		total := t.width * t.height
+		for word := range t.bits {
+			missing := ^atomic.LoadUint64(&t.bits[word])
+			for missing != 0 {
+				i := word*64 + bits.TrailingZeros64(missing)
+				if i >= total {
+					return
+				}
+				if !yield(Coordinate{X: t.minX + i%t.width, Y: t.minY + i/t.width}) {
+					return
+				}
+				missing &= missing - 1
+			}
+		}
+	}
+}
+
+// Filter returns a CoordinateSupplier that hands out the coordinates of cs that are not completed, skipping the others.
+// Coordinates are checked when they are handed out, so a coordinate completed later is still handed out by a repeating cs.
+// The returned supplier implements BatchCoordinateSupplier and ClosableCoordinateSupplier, forwarding to cs where it implements them.
+// If cs is a ProgressCoordinateSupplier, the returned supplier is one too, reporting the progress of cs.
+func (t *CompletionTracker) Filter(cs CoordinateSupplier) CoordinateSupplier {
+	filtered := &incompleteCoordinateSupplier{cs: cs, tracker: t}
+	if p, ok := cs.(ProgressCoordinateSupplier); ok {
+		return &incompleteProgressCoordinateSupplier{incompleteCoordinateSupplier: filtered, progress: p}
+	}
+	return filtered
+}
+
+// bit returns the word and the mask of the bit of a coordinate.
+func (t *CompletionTracker) bit(x, y int) (word int, mask uint64) {
+	x, y = x-t.minX, y-t.minY
+	if x < 0 || x >= t.width || y < 0 || y >= t.height {
+		panic(fmt.Sprintf("coordinate %d,%d outside of %dx%d grid at %d,%d", x+t.minX, y+t.minY, t.width, t.height, t.minX, t.minY))
+	}
+	i := y*t.width + x
+	return i / 64, 1 << (i % 64)
+}
+
+type incompleteCoordinateSupplier struct {
+	cs      CoordinateSupplier
+	tracker *CompletionTracker
+	closed  uint32
+}
+
+// Next returns the next coordinate of the wrapped supplier that is not completed.
+// It reports done once all coordinates of the grid are completed, or a whole pass of cs was skipped,
+// so it also stops when cs repeats.
+func (c *incompleteCoordinateSupplier) Next() (x, y int, done bool) {
+	for skipped := 0; !c.finished(skipped); skipped++ {
+		x, y, done = c.cs.Next()
+		if done {
+			break
+		}
+		if !c.tracker.IsDone(x, y) {
+			return x, y, false
+		}
+	}
+	return 0, 0, true
+}
+
+// NextN fills buf with the next coordinates of the wrapped supplier that are not completed.
+// If cs is a BatchCoordinateSupplier its batches are filtered, otherwise Next is called for each coordinate.
+func (c *incompleteCoordinateSupplier) NextN(buf []Coordinate) (n int, done bool) {
+	if len(buf) == 0 {
+		return 0, c.finished(0)
+	}
+	batch, ok := c.cs.(BatchCoordinateSupplier)
+	if !ok {
+		for n < len(buf) {
+			x, y, done := c.Next()
+			if done {
+				return n, n == 0
+			}
+			buf[n] = Coordinate{X: x, Y: y}
+			n++
+		}
+		return n, false
+	}
+
+	for skipped := 0; !c.finished(skipped); {
+		m, done := batch.NextN(buf)
+		if done {
+			break
+		}
+		// keep the coordinates not completed at the front of buf
+		for _, coordinate := range buf[:m] {
+			if !c.tracker.IsDone(coordinate.X, coordinate.Y) {
+				buf[n] = coordinate
+				n++
+			}
+		}
+		if n > 0 {
+			return n, false
+		}
+		skipped += m
+	}
+	return 0, true
+}
+
+// NextContext is like Next, but closes the supplier and reports done if ctx is done.
+func (c *incompleteCoordinateSupplier) NextContext(ctx context.Context) (x, y int, done bool) {
+	if ctx.Err() != nil {
+		c.Close()
+		return 0, 0, true
+	}
+	return c.Next()
+}
+
+// Close stops the supplier, and the wrapped supplier if it is a ClosableCoordinateSupplier.
+func (c *incompleteCoordinateSupplier) Close() error {
+	atomic.StoreUint32(&c.closed, 1)
+	if closable, ok := c.cs.(ClosableCoordinateSupplier); ok {
+		return closable.Close()
+	}
+	return nil
+}
+
+// finished reports whether the supplier was closed, all coordinates of the grid are completed,
+// or the caller skipped at least one pass of completed coordinates in a row.
+// A pass is Len coordinates of the wrapped supplier, or all coordinates of the grid if it does not report its Len.
+func (c *incompleteCoordinateSupplier) finished(skipped int) bool {
+	cells := c.tracker.width * c.tracker.height
+	pass := cells
+	if p, ok := c.cs.(ProgressCoordinateSupplier); ok {
+		pass = p.Len()
+	}
+	return atomic.LoadUint32(&c.closed) > 0 || c.tracker.CountDone() >= cells || skipped >= pass
+}
+
+// incompleteProgressCoordinateSupplier is an incompleteCoordinateSupplier wrapping a ProgressCoordinateSupplier.
+type incompleteProgressCoordinateSupplier struct {
+	*incompleteCoordinateSupplier
+	progress ProgressCoordinateSupplier
+}
+
+// Len returns the number of coordinates in one pass of the wrapped supplier, completed or not.
+func (c *incompleteProgressCoordinateSupplier) Len() int {
+	return c.progress.Len()
+}
+
+// HandedOut returns the number of coordinates the wrapped supplier handed out so far, including the skipped ones.
+func (c *incompleteProgressCoordinateSupplier) HandedOut() uint64 {
+	return c.progress.HandedOut()
+}
+
+// Remaining returns the number of coordinates left in the current pass of the wrapped supplier, including the completed ones.
+func (c *incompleteProgressCoordinateSupplier) Remaining() int {
+	return c.progress.Remaining()
+}
+
+// Epoch returns the number of completed passes of the wrapped supplier.
+func (c *incompleteProgressCoordinateSupplier) Epoch() uint64 {
+	return c.progress.Epoch()
+}
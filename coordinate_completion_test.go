@@ -0,0 +1,175 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestCompletionTracker(t *testing.T) {
+	tracker, err := NewCompletionTracker(CoordinateSupplierOptions{Width: 13, Height: 11})
+	require.NoError(t, err)
+
+	c, ok := tracker.FirstMissing()
+	require.True(t, ok)
+	require.Equal(t, Coordinate{0, 0}, c)
+
+	require.True(t, tracker.Complete(0, 0))
+	require.False(t, tracker.Complete(0, 0))
+	require.True(t, tracker.Complete(1, 0))
+	require.True(t, tracker.Complete(12, 10))
+	require.True(t, tracker.IsDone(1, 0))
+	require.False(t, tracker.IsDone(2, 0))
+	require.Equal(t, 3, tracker.CountDone())
+
+	c, ok = tracker.FirstMissing()
+	require.True(t, ok)
+	require.Equal(t, Coordinate{2, 0}, c)
+
+	var missing []Coordinate
+	for c := range tracker.Missing() {
+		require.False(t, tracker.IsDone(c.X, c.Y))
+		missing = append(missing, c)
+	}
+	require.Len(t, missing, 13*11-3)
+
+	for _, c := range missing {
+		tracker.Complete(c.X, c.Y)
+	}
+	_, ok = tracker.FirstMissing()
+	require.False(t, ok)
+	require.Equal(t, 13*11, tracker.CountDone())
+
+	require.Panics(t, func() { tracker.Complete(13, 0) })
+	require.Panics(t, func() { tracker.IsDone(0, -1) })
+
+	_, err = NewCompletionTracker(CoordinateSupplierOptions{Width: 0, Height: 1})
+	require.Error(t, err)
+}
+
+func TestCompletionTracker_Filter_Rerun(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 100, Height: 100, Order: Random}
+	tracker, err := NewCompletionTracker(testOpts)
+	require.NoError(t, err)
+
+	sweep := func(fail func(x, y int) bool) {
+		cs, err := NewCoordinateSupplier(testOpts)
+		require.NoError(t, err)
+		cs = tracker.Filter(cs)
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for x, y, done := cs.Next(); !done; x, y, done = cs.Next() {
+					if !fail(x, y) {
+						tracker.Complete(x, y)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	// the first sweep fails for every seventh coordinate
+	sweep(func(x, y int) bool { return (x+y*testOpts.Width)%7 == 0 })
+	require.Equal(t, 100*100-1429, tracker.CountDone())
+
+	// re-running the sweep only hands out what is missing
+	var missing []Coordinate
+	for c := range tracker.Missing() {
+		missing = append(missing, c)
+	}
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 100, Height: 100, Order: Asc})
+	require.NoError(t, err)
+	require.Equal(t, missing, consumeAll(t, tracker.Filter(cs)))
+
+	sweep(func(x, y int) bool { return false })
+	require.Equal(t, 100*100, tracker.CountDone())
+}
+
+func TestCompletionTracker_Origin(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 6, Height: 4, MinX: -3, MinY: -2, Order: Random}
+	tracker, err := NewCompletionTracker(opts)
+	require.NoError(t, err)
+
+	c, ok := tracker.FirstMissing()
+	require.True(t, ok)
+	require.Equal(t, Coordinate{-3, -2}, c)
+
+	cs, err := NewCoordinateSupplier(opts)
+	require.NoError(t, err)
+	for x, y, done := cs.Next(); !done; x, y, done = cs.Next() {
+		require.True(t, tracker.Complete(x, y))
+	}
+	require.Equal(t, 24, tracker.CountDone())
+	require.Panics(t, func() { tracker.IsDone(3, 0) })
+	require.Panics(t, func() { tracker.IsDone(0, -3) })
+}
+
+func TestCompletionTracker_Filter_Repeat(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 2, Height: 2, Order: Asc, Repeat: true}
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			tracker, err := NewCompletionTracker(opts)
+			require.NoError(t, err)
+			cs, err := supplier.new(opts)
+			require.NoError(t, err)
+			filtered := tracker.Filter(cs)
+
+			// the repeating supplier is done once every coordinate is completed
+			for x, y, done := filtered.Next(); !done; x, y, done = filtered.Next() {
+				tracker.Complete(x, y)
+			}
+			require.Equal(t, 4, tracker.CountDone())
+		})
+	}
+
+	// a repeating supplier of only part of the grid is done after a pass without incomplete coordinates
+	opts.Partition = PartitionOptions{Count: 2, Index: 1}
+	partTracker, err := NewCompletionTracker(opts)
+	require.NoError(t, err)
+	cs, err := NewCoordinateSupplier(opts)
+	require.NoError(t, err)
+	filtered := partTracker.Filter(cs).(BatchCoordinateSupplier)
+	buf := make([]Coordinate, 3)
+	for n, done := filtered.NextN(buf); !done; n, done = filtered.NextN(buf) {
+		for _, c := range buf[:n] {
+			partTracker.Complete(c.X, c.Y)
+		}
+	}
+	require.Equal(t, 2, partTracker.CountDone())
+	_, _, done := filtered.Next()
+	require.True(t, done)
+}
+
+func TestCompletionTracker_Filter_Capabilities(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 4, Height: 4, Order: Asc, Repeat: true}
+	tracker, err := NewCompletionTracker(opts)
+	require.NoError(t, err)
+	tracker.Complete(1, 0)
+	cs, err := NewCoordinateSupplier(opts)
+	require.NoError(t, err)
+	filtered := tracker.Filter(cs)
+
+	buf := make([]Coordinate, 2)
+	n, done := filtered.(BatchCoordinateSupplier).NextN(buf)
+	require.False(t, done)
+	require.Equal(t, []Coordinate{{0, 0}}, buf[:n])
+
+	progress := filtered.(ProgressCoordinateSupplier)
+	require.Equal(t, 16, progress.Len())
+	require.Equal(t, uint64(2), progress.HandedOut())
+	require.Equal(t, 14, progress.Remaining())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, done = filtered.(ClosableCoordinateSupplier).NextContext(ctx)
+	require.True(t, done)
+	_, _, done = filtered.Next()
+	require.True(t, done)
+	_, _, done = cs.Next()
+	require.True(t, done)
+}
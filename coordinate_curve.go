@@ -0,0 +1,194 @@
+package coordinate_supplier
+
+import "slices"
+
+// makeHilbertCoordinates returns coordinates along a Hilbert curve.
+// The curve is built over the smallest power-of-two square that covers the grid and then clipped to width x height,
+// so every cell is still visited exactly once when the grid is not a power-of-two square.
+func makeHilbertCoordinates(width, height int) []Coordinate {
+	coordinates := make([]Coordinate, 0, width*height)
+	size := curveSize(width, height)
+	var walk func(x0, y0, xi, xj, yi, yj int)
+	walk = func(x0, y0, xi, xj, yi, yj int) {
+		// the curve enters the square at corner (x0,y0) and leaves it at the end of side (xi,xj), (yi,yj) is the other side
+		if !squareInGrid(x0, y0, xi, xj, yi, yj, width, height) {
+			return
+		}
+		if abs(xi+xj) == 1 {
+			coordinates = append(coordinates, Coordinate{
+				X: x0 + minInt(0, xi) + minInt(0, yi),
+				Y: y0 + minInt(0, xj) + minInt(0, yj),
+			})
+			return
+		}
+		hxi, hxj, hyi, hyj := xi/2, xj/2, yi/2, yj/2
+		walk(x0, y0, hyi, hyj, hxi, hxj)
+		walk(x0+hyi, y0+hyj, hxi, hxj, hyi, hyj)
+		walk(x0+hxi+hyi, y0+hxj+hyj, hxi, hxj, hyi, hyj)
+		walk(x0+xi+hyi, y0+xj+hyj, -hyi, -hyj, -hxi, -hxj)
+	}
+	walk(0, 0, size, 0, 0, size)
+	return coordinates
+}
+
+// makeZOrderCoordinates returns coordinates in Z-order, sorted by the Morton code formed by interleaving the bits of X and Y.
+// Like makeHilbertCoordinates the curve is clipped to width x height, which skips whole quadrants outside a rectangular grid
+// instead of visiting every cell of the covering square.
+func makeZOrderCoordinates(width, height int) []Coordinate {
+	coordinates := make([]Coordinate, 0, width*height)
+	var walk func(x0, y0, size int)
+	walk = func(x0, y0, size int) {
+		if x0 >= width || y0 >= height {
+			return
+		}
+		if size == 1 {
+			coordinates = append(coordinates, Coordinate{X: x0, Y: y0})
+			return
+		}
+		half := size / 2
+		walk(x0, y0, half)
+		walk(x0+half, y0, half)
+		walk(x0, y0+half, half)
+		walk(x0+half, y0+half, half)
+	}
+	walk(0, 0, curveSize(width, height))
+	return coordinates
+}
+
+// curveSize returns the smallest power of two that is at least width and height.
+func curveSize(width, height int) int {
+	size := 1
+	for size < width || size < height {
+		size *= 2
+	}
+	return size
+}
+
+// squareInGrid reports whether the square spanned from (x0,y0) by sides (xi,xj) and (yi,yj) overlaps the grid.
+func squareInGrid(x0, y0, xi, xj, yi, yj, width, height int) bool {
+	minX := x0 + minInt(0, xi) + minInt(0, yi)
+	minY := y0 + minInt(0, xj) + minInt(0, yj)
+	maxX := x0 + maxInt(0, xi) + maxInt(0, yi)
+	maxY := y0 + maxInt(0, xj) + maxInt(0, yj)
+	return minX < width && minY < height && maxX > 0 && maxY > 0
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// makeCurveIndexes returns the indexes of all cells of a grid with the given size along each axis, sorted by their key along a curve.
+// Like makeHilbertCoordinates the curve covers the smallest power-of-two cube around the grid, and is clipped to the grid.
+func makeCurveIndexes(shape []int, cells int, key func(axes []uint64, bits int) []uint64) []int {
+	bits := 0
+	for _, size := range shape {
+		for 1<<bits < size {
+			bits++
+		}
+	}
+	keys := make([][]uint64, cells)
+	axes := make([]uint64, len(shape))
+	for i := range keys {
+		rest := i
+		for axis, size := range shape {
+			axes[axis] = uint64(rest % size)
+			rest /= size
+		}
+		keys[i] = key(axes, bits)
+	}
+
+	indexes := make([]int, cells)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	slices.SortFunc(indexes, func(a, b int) int {
+		return slices.Compare(keys[a], keys[b])
+	})
+	return indexes
+}
+
+// mortonKey returns the Morton code of a cell, interleaving the bits of its axes with the last axis most significant.
+// With two axes this is the order of makeZOrderCoordinates.
+func mortonKey(axes []uint64, bits int) []uint64 {
+	return interleaveBits(axes, bits)
+}
+
+// hilbertKey returns the position of a cell along a Hilbert curve through the cube with a side of 1<<bits.
+// It uses the transform from John Skilling, "Programming the Hilbert curve", which works for any number of axes.
+func hilbertKey(axes []uint64, bits int) []uint64 {
+	if bits == 0 {
+		return nil
+	}
+	// the last axis is the first axis of the transform, to match mortonKey
+	n := len(axes)
+	x := make([]uint64, n)
+	for i := range x {
+		x[i] = axes[n-1-i]
+	}
+
+	// inverse undo excess work
+	m := uint64(1) << (bits - 1)
+	for q := m; q > 1; q >>= 1 {
+		p := q - 1
+		for i := range x {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+	// gray encode
+	for i := 1; i < n; i++ {
+		x[i] ^= x[i-1]
+	}
+	var t uint64
+	for q := m; q > 1; q >>= 1 {
+		if x[n-1]&q != 0 {
+			t ^= q - 1
+		}
+	}
+	for i := range x {
+		x[i] ^= t
+	}
+
+	// x[0] is the most significant axis of the transform, interleaveBits takes the last axis first
+	slices.Reverse(x)
+	return interleaveBits(x, bits)
+}
+
+// interleaveBits returns the bits of the axes interleaved from the highest bit down, with the last axis most significant,
+// packed into words from the most significant bit of the first word.
+func interleaveBits(axes []uint64, bits int) []uint64 {
+	total := bits * len(axes)
+	key := make([]uint64, (total+63)/64)
+	at := 0
+	for bit := bits - 1; bit >= 0; bit-- {
+		for axis := len(axes) - 1; axis >= 0; axis-- {
+			if axes[axis]>>bit&1 != 0 {
+				key[at/64] |= 1 << (63 - at%64)
+			}
+			at++
+		}
+	}
+	return key
+}
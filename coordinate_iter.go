@@ -0,0 +1,29 @@
+package coordinate_supplier
+
+import "iter"
+
+// All returns an iterator over the coordinates handed out by cs, for use with `for c := range All(cs)`.
+// Each iteration calls cs.Next, breaking out of the loop stops calling it. Iterating a repeating supplier never ends by itself.
+func All(cs CoordinateSupplier) iter.Seq[Coordinate] {
+	return func(yield func(Coordinate) bool) {
+		for x, y, done := cs.Next(); !done; x, y, done = cs.Next() {
+			if !yield(Coordinate{X: x, Y: y}) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate is like All, but also yields the count of coordinates this iterator handed out before the current one.
+// When cs is shared with other consumers, the index does not match the position of the coordinate in the Order.
+func Enumerate(cs CoordinateSupplier) iter.Seq2[int, Coordinate] {
+	return func(yield func(int, Coordinate) bool) {
+		i := 0
+		for c := range All(cs) {
+			if !yield(i, c) {
+				return
+			}
+			i++
+		}
+	}
+}
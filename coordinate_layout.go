@@ -0,0 +1,248 @@
+package coordinate_supplier
+
+import (
+	"fmt"
+	"sort"
+)
+
+// lattice is a part of the grid: every stepX-th column and every stepY-th row, from the cell at x0,y0 relative to the origin of the grid.
+// The Order is laid over its width x height cells and shuffled with seed.
+type lattice struct {
+	x0, y0        int
+	stepX, stepY  int
+	width, height int
+	seed          int64
+}
+
+// layout is how the coordinates handed out with some options are laid out over the grid.
+// The coordinates of the lattices are handed out one lattice after the other,
+// and of those count coordinates are handed out, from first and every stride.
+type layout struct {
+	minX, minY    int
+	lattices      []lattice
+	partitioned   bool
+	first, stride int
+	count         int
+}
+
+// layout returns the layout of the coordinates handed out with the steps and Partition of the options.
+func (opts CoordinateSupplierOptions) layout() (layout, error) {
+	l := layout{minX: opts.MinX, minY: opts.MinY, stride: 1}
+	if opts.StepX < 0 || opts.StepY < 0 {
+		return l, fmt.Errorf("minimum step is 1")
+	}
+	stepX, stepY := maxInt(opts.StepX, 1), maxInt(opts.StepY, 1)
+	if opts.Progressive {
+		if !powerOfTwo(stepX) || !powerOfTwo(stepY) {
+			return l, fmt.Errorf("progressive steps must be powers of two")
+		}
+		l.lattices = refinementLattices(opts.Width, opts.Height, stepX, stepY, opts.Seed)
+	} else {
+		l.lattices = []lattice{{stepX: stepX, stepY: stepY, width: ceilDiv(opts.Width, stepX), height: ceilDiv(opts.Height, stepY), seed: opts.Seed}}
+	}
+	cells := 0
+	for _, t := range l.lattices {
+		cells += t.width * t.height
+	}
+	l.count = cells
+
+	p := opts.Partition
+	if p == (PartitionOptions{}) {
+		return l, nil
+	}
+	if p.Count < 1 {
+		return l, fmt.Errorf("minimum partitions is 1")
+	}
+	if p.Index < 0 || p.Index >= p.Count {
+		return l, fmt.Errorf("partition %d outside of %d partitions", p.Index, p.Count)
+	}
+	if p.Count > cells {
+		return l, fmt.Errorf("more partitions than coordinates")
+	}
+
+	l.partitioned = true
+	switch p.Partitioning {
+	case PartitionStrided:
+		l.first, l.stride = p.Index, p.Count
+		l.count = (cells - p.Index + p.Count - 1) / p.Count
+	case PartitionContiguous:
+		l.first = p.Index * cells / p.Count
+		l.count = (p.Index+1)*cells/p.Count - l.first
+	case PartitionTiles:
+		if len(l.lattices) > 1 {
+			return l, fmt.Errorf("tiles partitioning is not supported with progressive refinement")
+		}
+		t := &l.lattices[0]
+		rows, ok := tileLayout(t.width, t.height, p.Count)
+		if !ok {
+			return l, fmt.Errorf("can not split %dx%d grid into %d tiles", t.width, t.height, p.Count)
+		}
+		row, first, columns := tileRow(p.Count, rows, p.Index)
+		x0, width := splitRange(t.width, columns, p.Index-first)
+		y0, height := splitRange(t.height, rows, row)
+		t.x0, t.y0, t.width, t.height = x0*t.stepX, y0*t.stepY, width, height
+		if t.seed != 0 {
+			// every tile gets its own permutation
+			t.seed ^= int64(mix64(uint64(p.Index) + 1))
+		}
+		// the tile is the only lattice left, all of it is handed out
+		l.partitioned = false
+		l.count = width * height
+	default:
+		return l, fmt.Errorf("unknown partitioning specified")
+	}
+	return l, nil
+}
+
+// refinementLattices returns the lattices of progressive refinement: first the coarse lattice with steps stepX and stepY,
+// then for every halving of the steps the cells of the finer lattice that are not in the coarser ones, until every cell is covered once.
+// Random orders get another permutation for every lattice, unless seed is zero.
+func refinementLattices(width, height, stepX, stepY int, seed int64) []lattice {
+	var lattices []lattice
+	add := func(x0, y0 int) {
+		if x0 >= width || y0 >= height {
+			return
+		}
+		t := lattice{x0: x0, y0: y0, stepX: stepX, stepY: stepY, width: ceilDiv(width-x0, stepX), height: ceilDiv(height-y0, stepY), seed: seed}
+		if seed != 0 && len(lattices) > 0 {
+			t.seed ^= int64(mix64(uint64(len(lattices))))
+		}
+		lattices = append(lattices, t)
+	}
+	add(0, 0)
+	for stepX > 1 || stepY > 1 {
+		// the finer lattice is the coarser one moved by half a step along the axes that are refined
+		halfX, halfY := maxInt(stepX/2, 1), maxInt(stepY/2, 1)
+		if halfX < stepX {
+			add(halfX, 0)
+		}
+		if halfY < stepY {
+			add(0, halfY)
+		}
+		if halfX < stepX && halfY < stepY {
+			add(halfX, halfY)
+		}
+		stepX, stepY = halfX, halfY
+	}
+	return lattices
+}
+
+// coordinates returns the coordinates of the lattice in the Order with the Traversal applied, counted in cells of the lattice.
+func (t lattice) coordinates(order Order, traversal Traversal) ([]Coordinate, error) {
+	width, height := t.width, t.height
+	if traversal.ColumnMajor {
+		width, height = height, width
+	}
+	cs, err := makeCoordinateList(width, height, order, t.seed)
+	if err != nil {
+		return nil, err
+	}
+	if traversal.ColumnMajor {
+		transposeCoordinates(cs)
+	}
+	if traversal.ReverseX {
+		for i := range cs {
+			cs[i].X = t.width - 1 - cs[i].X
+		}
+	}
+	if traversal.ReverseY {
+		for i := range cs {
+			cs[i].Y = t.height - 1 - cs[i].Y
+		}
+	}
+	return cs, nil
+}
+
+// coordinateFunc is like coordinates, but returns a function that computes the coordinate at an index.
+func (t lattice) coordinateFunc(order Order, traversal Traversal) (func(i int) Coordinate, error) {
+	width, height := t.width, t.height
+	if traversal.ColumnMajor {
+		width, height = height, width
+	}
+	at, ok := coordinateFunc(width, height, order, t.seed)
+	if !ok {
+		return nil, fmt.Errorf("order %s has no closed form", OrderToString(order))
+	}
+	if traversal == (Traversal{}) {
+		return at, nil
+	}
+	return func(i int) Coordinate {
+		c := at(i)
+		if traversal.ColumnMajor {
+			c.X, c.Y = c.Y, c.X
+		}
+		if traversal.ReverseX {
+			c.X = t.width - 1 - c.X
+		}
+		if traversal.ReverseY {
+			c.Y = t.height - 1 - c.Y
+		}
+		return c
+	}, nil
+}
+
+// moved reports whether coordinates of lattice t have to be placed on the grid, or are in cells of the grid already.
+func (l layout) moved(t lattice) bool {
+	return l.minX != 0 || l.minY != 0 || t.x0 != 0 || t.y0 != 0 || t.stepX != 1 || t.stepY != 1
+}
+
+// place returns the coordinate on the grid of a coordinate counted in cells of lattice t.
+func (l layout) place(t lattice, c Coordinate) Coordinate {
+	return Coordinate{X: l.minX + t.x0 + c.X*t.stepX, Y: l.minY + t.y0 + c.Y*t.stepY}
+}
+
+// selectCoordinates returns the coordinates handed out, from the coordinates of all lattices placed on the grid.
+func (l layout) selectCoordinates(cs []Coordinate) []Coordinate {
+	if !l.partitioned {
+		return cs
+	}
+	selected := make([]Coordinate, l.count)
+	for i := range selected {
+		selected[i] = cs[l.first+i*l.stride]
+	}
+	return selected
+}
+
+// coordinateFunc returns a function that computes the coordinate at an index of the coordinates handed out with the layout.
+func (l layout) coordinateFunc(order Order, traversal Traversal) (func(i int) Coordinate, error) {
+	ats := make([]func(i int) Coordinate, len(l.lattices))
+	starts := make([]int, len(l.lattices))
+	start := 0
+	for k, t := range l.lattices {
+		at, err := t.coordinateFunc(order, traversal)
+		if err != nil {
+			return nil, err
+		}
+		if l.moved(t) {
+			t, latticeAt := t, at
+			at = func(i int) Coordinate {
+				return l.place(t, latticeAt(i))
+			}
+		}
+		ats[k], starts[k] = at, start
+		start += t.width * t.height
+	}
+
+	at := ats[0]
+	if len(ats) > 1 {
+		at = func(i int) Coordinate {
+			// the last lattice starting at or before i
+			k := sort.Search(len(starts), func(k int) bool { return starts[k] > i }) - 1
+			return ats[k](i - starts[k])
+		}
+	}
+	if !l.partitioned {
+		return at, nil
+	}
+	return func(i int) Coordinate {
+		return at(l.first + i*l.stride)
+	}, nil
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func powerOfTwo(v int) bool {
+	return v > 0 && v&(v-1) == 0
+}
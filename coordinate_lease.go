@@ -0,0 +1,200 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUnknownLease is returned when acknowledging a lease that is not outstanding,
+// because it was already acknowledged, or it expired and its coordinate was handed out again.
+var ErrUnknownLease = errors.New("unknown lease")
+
+// Lease is a coordinate handed out by a LeaseCoordinateSupplier.
+// Its ID must be acknowledged with Ack once the coordinate is processed.
+type Lease struct {
+	ID         uint64
+	Coordinate Coordinate
+}
+
+// LeaseCoordinateSupplier hands out the coordinates of a CoordinateSupplier as leases, so every coordinate is processed at least once.
+// A lease that is not acknowledged with Ack within the timeout, or that is given back with Nack, is handed out again.
+// It is safe for concurrent use.
+type LeaseCoordinateSupplier struct {
+	cs        CoordinateSupplier
+	timeout   time.Duration
+	mu        sync.Mutex
+	nextID    uint64
+	leases    map[uint64]leaseEntry // outstanding leases
+	issued    []uint64              // IDs of outstanding leases in the order they were issued, which is also the order they expire
+	requeued  []Coordinate          // coordinates of expired and nacked leases, to hand out again
+	exhausted bool                  // cs is done
+	changed   chan struct{}         // closed and replaced when a lease is acknowledged or given back
+}
+
+type leaseEntry struct {
+	coordinate Coordinate
+	deadline   time.Time
+}
+
+// NewLeaseCoordinateSupplier returns a LeaseCoordinateSupplier handing out the coordinates of cs.
+// Leases expire after timeout, a timeout of 0 means leases never expire and are only handed out again after Nack.
+func NewLeaseCoordinateSupplier(cs CoordinateSupplier, timeout time.Duration) *LeaseCoordinateSupplier {
+	return &LeaseCoordinateSupplier{
+		cs:      cs,
+		timeout: timeout,
+		leases:  make(map[uint64]leaseEntry),
+		changed: make(chan struct{}),
+	}
+}
+
+// Next returns a lease on the next coordinate to be processed.
+// When all remaining coordinates are leased, it blocks until one of the leases is given back or expires.
+// If done is true, every coordinate was acknowledged and Next should not be called any longer.
+func (l *LeaseCoordinateSupplier) Next() (lease Lease, done bool) {
+	return l.NextContext(context.Background())
+}
+
+// NextContext is like Next, but stops blocking and reports done when ctx is done.
+func (l *LeaseCoordinateSupplier) NextContext(ctx context.Context) (lease Lease, done bool) {
+	for {
+		if ctx.Err() != nil {
+			return Lease{}, true
+		}
+
+		l.mu.Lock()
+		now := time.Now()
+		l.expire(now)
+		if c, ok := l.take(); ok {
+			lease = l.issue(c, now)
+			l.mu.Unlock()
+			return lease, false
+		}
+		if len(l.leases) == 0 {
+			l.mu.Unlock()
+			return Lease{}, true
+		}
+
+		// wait for a lease to be given back, or the first one to expire
+		changed := l.changed
+		var expired <-chan time.Time
+		var timer *time.Timer
+		if l.timeout > 0 {
+			timer = time.NewTimer(l.leases[l.issued[0]].deadline.Sub(now))
+			expired = timer.C
+		}
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-expired:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Ack acknowledges that the coordinate of the lease was processed, so it is not handed out again.
+// A lease past its deadline is expired, even if Next did not requeue its coordinate yet.
+func (l *LeaseCoordinateSupplier) Ack(id uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(time.Now())
+	if _, ok := l.leases[id]; !ok {
+		return ErrUnknownLease
+	}
+	l.release(id)
+	return nil
+}
+
+// Nack gives back the lease without processing its coordinate, so it is handed out again right away.
+func (l *LeaseCoordinateSupplier) Nack(id uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expire(time.Now())
+	lease, ok := l.leases[id]
+	if !ok {
+		return ErrUnknownLease
+	}
+	l.release(id)
+	l.requeued = append(l.requeued, lease.coordinate)
+	return nil
+}
+
+// Outstanding returns the number of leases that are neither acknowledged nor expired.
+func (l *LeaseCoordinateSupplier) Outstanding() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.leases)
+}
+
+// expire requeues the coordinates of leases past their deadline, the caller must hold the lock.
+// It also drops IDs of leases that are no longer outstanding from the front of issued.
+func (l *LeaseCoordinateSupplier) expire(now time.Time) {
+	for len(l.issued) > 0 {
+		lease, ok := l.leases[l.issued[0]]
+		if ok && l.timeout > 0 && !now.Before(lease.deadline) {
+			delete(l.leases, l.issued[0])
+			l.requeued = append(l.requeued, lease.coordinate)
+			ok = false
+		}
+		if ok {
+			return
+		}
+		l.issued = l.issued[1:]
+	}
+}
+
+// release removes the outstanding lease id and wakes up waiting callers of Next, the caller must hold the lock.
+// The IDs of released leases are dropped from issued once they make up most of it,
+// so it does not grow without bound while an early lease stays outstanding.
+func (l *LeaseCoordinateSupplier) release(id uint64) {
+	delete(l.leases, id)
+	if len(l.issued) > 2*len(l.leases)+16 {
+		outstanding := l.issued[:0]
+		for _, id := range l.issued {
+			if _, ok := l.leases[id]; ok {
+				outstanding = append(outstanding, id)
+			}
+		}
+		l.issued = outstanding
+	}
+	l.notify()
+}
+
+// take returns the next coordinate to lease, requeued ones first, the caller must hold the lock.
+func (l *LeaseCoordinateSupplier) take() (Coordinate, bool) {
+	if len(l.requeued) > 0 {
+		c := l.requeued[0]
+		l.requeued = l.requeued[1:]
+		return c, true
+	}
+	if !l.exhausted {
+		x, y, done := l.cs.Next()
+		if !done {
+			return Coordinate{X: x, Y: y}, true
+		}
+		l.exhausted = true
+	}
+	return Coordinate{}, false
+}
+
+// issue leases c until the timeout from now, the caller must hold the lock.
+func (l *LeaseCoordinateSupplier) issue(c Coordinate, now time.Time) Lease {
+	l.nextID++
+	l.leases[l.nextID] = leaseEntry{coordinate: c, deadline: now.Add(l.timeout)}
+	l.issued = append(l.issued, l.nextID)
+	return Lease{ID: l.nextID, Coordinate: c}
+}
+
+// notify wakes up callers of Next waiting for a lease to be given back, the caller must hold the lock.
+func (l *LeaseCoordinateSupplier) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
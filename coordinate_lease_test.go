@@ -0,0 +1,174 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLeaseCoordinateSupplier_Ack(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 10, Height: 10, Order: Asc})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, time.Minute)
+
+	var coords []Coordinate
+	for lease, done := l.Next(); !done; lease, done = l.Next() {
+		coords = append(coords, lease.Coordinate)
+		require.NoError(t, l.Ack(lease.ID))
+		require.ErrorIs(t, l.Ack(lease.ID), ErrUnknownLease)
+	}
+	requireEachCoordinateOnce(t, 10, 10, coords)
+	require.Equal(t, 0, l.Outstanding())
+}
+
+func TestLeaseCoordinateSupplier_Nack(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 2, Height: 1, Order: Asc})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, 0)
+
+	first, done := l.Next()
+	require.False(t, done)
+	require.Equal(t, Coordinate{0, 0}, first.Coordinate)
+	require.NoError(t, l.Nack(first.ID))
+	require.ErrorIs(t, l.Nack(first.ID), ErrUnknownLease)
+
+	// the given back coordinate is handed out again first, with a new lease
+	again, done := l.Next()
+	require.False(t, done)
+	require.Equal(t, Coordinate{0, 0}, again.Coordinate)
+	require.NotEqual(t, first.ID, again.ID)
+
+	second, done := l.Next()
+	require.False(t, done)
+	require.Equal(t, Coordinate{1, 0}, second.Coordinate)
+	require.NoError(t, l.Ack(second.ID))
+
+	// with one lease outstanding, Next blocks until it is given back
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		l.Nack(again.ID)
+	}()
+	last, done := l.Next()
+	require.False(t, done)
+	require.Equal(t, Coordinate{0, 0}, last.Coordinate)
+	require.NoError(t, l.Ack(last.ID))
+
+	_, done = l.Next()
+	require.True(t, done)
+}
+
+func TestLeaseCoordinateSupplier_Timeout(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 1, Height: 1, Order: Asc})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, 20*time.Millisecond)
+
+	lost, done := l.Next()
+	require.False(t, done)
+
+	// the lease is not acknowledged, so it is handed out again after it expires
+	start := time.Now()
+	again, done := l.Next()
+	require.False(t, done)
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	require.Equal(t, lost.Coordinate, again.Coordinate)
+	require.ErrorIs(t, l.Ack(lost.ID), ErrUnknownLease)
+	require.NoError(t, l.Ack(again.ID))
+
+	_, done = l.Next()
+	require.True(t, done)
+}
+
+func TestLeaseCoordinateSupplier_Ack_Past_Deadline(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 2, Height: 1, Order: Asc})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, 10*time.Millisecond)
+
+	late, done := l.Next()
+	require.False(t, done)
+	nacked, done := l.Next()
+	require.False(t, done)
+	time.Sleep(20 * time.Millisecond)
+
+	// the leases expired before Next was called again, so they can not be acknowledged or given back any longer
+	require.ErrorIs(t, l.Ack(late.ID), ErrUnknownLease)
+	require.ErrorIs(t, l.Nack(nacked.ID), ErrUnknownLease)
+	require.Equal(t, 0, l.Outstanding())
+
+	var coords []Coordinate
+	for lease, done := l.Next(); !done; lease, done = l.Next() {
+		coords = append(coords, lease.Coordinate)
+		require.NoError(t, l.Ack(lease.ID))
+	}
+	require.Equal(t, []Coordinate{late.Coordinate, nacked.Coordinate}, coords)
+}
+
+func TestLeaseCoordinateSupplier_Issued_Bounded(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 100, Height: 100, Order: Asc})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, 0)
+
+	// the first lease stays outstanding while all the others are acknowledged
+	first, done := l.Next()
+	require.False(t, done)
+	for i := 1; i < 100*100; i++ {
+		lease, done := l.Next()
+		require.False(t, done)
+		require.NoError(t, l.Ack(lease.ID))
+	}
+	require.Equal(t, 1, l.Outstanding())
+	require.LessOrEqual(t, len(l.issued), 2*l.Outstanding()+16)
+
+	require.NoError(t, l.Ack(first.ID))
+	_, done = l.Next()
+	require.True(t, done)
+}
+
+func TestLeaseCoordinateSupplier_NextContext(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 1, Height: 1, Order: Asc})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, 0)
+
+	_, done := l.Next()
+	require.False(t, done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, done = l.NextContext(ctx)
+	require.True(t, done)
+	require.Equal(t, 1, l.Outstanding())
+}
+
+func TestLeaseCoordinateSupplier_Crashing_Workers(t *testing.T) {
+	cs, err := NewCoordinateSupplier(CoordinateSupplierOptions{Width: 30, Height: 30, Order: Random})
+	require.NoError(t, err)
+	l := NewLeaseCoordinateSupplier(cs, 5*time.Millisecond)
+
+	// every third lease is lost by a crashing worker, nacked or acknowledged
+	mu := sync.Mutex{}
+	processed := make(map[Coordinate]int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lease, done := l.Next(); !done; lease, done = l.Next() {
+				switch lease.ID % 3 {
+				case 0:
+				case 1:
+					l.Nack(lease.ID)
+				case 2:
+					mu.Lock()
+					processed[lease.Coordinate]++
+					mu.Unlock()
+					l.Ack(lease.ID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, processed, 900)
+	require.Equal(t, 0, l.Outstanding())
+}
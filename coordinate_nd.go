@@ -0,0 +1,116 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// CoordinateSupplierND provides coordinates in a grid with any number of dimensions, like CoordinateSupplier does in a XY grid.
+type CoordinateSupplierND interface {
+	// Next should be called repeatedly to iterate through each coordinate.
+	// If done is false, the returned coordinate should be used, it holds one value for each dimension of the grid.
+	// It is a new slice on every call, owned by the caller.
+	// If done is true, the returned coordinate should be discarded and Next should not be called any longer.
+	Next() (coordinate []int, done bool)
+}
+
+// CoordinateSupplierOptionsND control the way coordinates are handed out in a grid with any number of dimensions.
+type CoordinateSupplierOptionsND struct {
+	Shape  []int // size of the grid along each dimension, for example []int{4, 8, 16, 3}
+	Order  Order // order that coordinates will be handed out (Asc, Desc, Random, RandomBijection, Hilbert or ZOrder, with two dimensions any Order)
+	Repeat bool  // if each coordinate should be handed out exactly once, or if iterating should loop through indefinitely
+	Seed   int64 // seed for Random orders, the same seed always hands out the same permutation. Zero picks a random one
+}
+
+type coordinateSupplierND struct {
+	shape    []int
+	sequence sequence[int] // indexes of the cells, in ascending order the first dimension varies fastest
+}
+
+// NewCoordinateSupplierND returns a CoordinateSupplierND synchronized with atomic.AddUint64, like NewCoordinateSupplierAtomic.
+// Ascending order hands out the first dimension first, with two dimensions it hands out X then Y like a CoordinateSupplier.
+// It also implements Close and NextContext, like a ClosableCoordinateSupplier.
+func NewCoordinateSupplierND(opts CoordinateSupplierOptionsND) (CoordinateSupplierND, error) {
+	if err := validateShape(opts.Shape); err != nil {
+		return nil, err
+	}
+	indexes, err := makeIndexOrder(opts.Shape, opts.Order, opts.Seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed make coordinate list: %w", err)
+	}
+	return &coordinateSupplierND{
+		shape:    append([]int(nil), opts.Shape...),
+		sequence: sequence[int]{items: indexes, total: uint64(len(indexes)), repeat: opts.Repeat},
+	}, nil
+}
+
+// MakeCoordinateListND returns a slice of coordinates, with each item representing one cell in the grid,
+// in the order a CoordinateSupplierND created with opts hands them out.
+func MakeCoordinateListND(opts CoordinateSupplierOptionsND) ([][]int, error) {
+	if err := validateShape(opts.Shape); err != nil {
+		return nil, err
+	}
+	indexes, err := makeIndexOrder(opts.Shape, opts.Order, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+	cs := make([][]int, len(indexes))
+	for i, index := range indexes {
+		cs[i] = cellCoordinate(opts.Shape, index)
+	}
+	return cs, nil
+}
+
+// Next returns the next coordinate to be supplied.
+// It may be possible to receive some coordinates slightly out of order when called concurrently.
+func (c *coordinateSupplierND) Next() (coordinate []int, done bool) {
+	index, done := c.sequence.next()
+	if done {
+		return nil, true
+	}
+	return cellCoordinate(c.shape, index), false
+}
+
+// NextContext returns the next coordinate to be supplied, or closes the supplier if ctx is done.
+func (c *coordinateSupplierND) NextContext(ctx context.Context) (coordinate []int, done bool) {
+	index, done := c.sequence.nextContext(ctx)
+	if done {
+		return nil, true
+	}
+	return cellCoordinate(c.shape, index), false
+}
+
+// Close marks the supplier as done for all callers.
+func (c *coordinateSupplierND) Close() error {
+	c.sequence.close()
+	return nil
+}
+
+// validateShape checks that a grid has at least one dimension, at least one cell along each, and not too many cells to list.
+func validateShape(shape []int) error {
+	if len(shape) < 1 {
+		return fmt.Errorf("minimum dimensions is 1")
+	}
+	cells := 1
+	for dimension, size := range shape {
+		if size < 1 {
+			return fmt.Errorf("minimum size of dimension %d is 1", dimension)
+		}
+		if cells > math.MaxInt32/size {
+			return fmt.Errorf("too many cells in grid")
+		}
+		cells *= size
+	}
+	return nil
+}
+
+// cellCoordinate returns the coordinate of the cell at index of the grid in ascending order.
+func cellCoordinate(shape []int, index int) []int {
+	coordinate := make([]int, len(shape))
+	for dimension, size := range shape {
+		coordinate[dimension] = index % size
+		index /= size
+	}
+	return coordinate
+}
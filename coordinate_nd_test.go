@@ -0,0 +1,141 @@
+package coordinate_supplier
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func Test_Coordinate_Supplier_ND_EachCoordinateOnce(t *testing.T) {
+	for _, order := range orders3DToTest {
+		for _, shape := range [][]int{{1}, {9}, {4, 8, 3, 2}, {3, 1, 2, 2, 3}, {2, 2, 2, 2, 2, 2}} {
+			t.Run(fmt.Sprintf("%s-%v", OrderToString(order), shape), func(t *testing.T) {
+				cs, err := NewCoordinateSupplierND(CoordinateSupplierOptionsND{Shape: shape, Order: order})
+				require.NoError(t, err)
+				requireEachCoordinateNDOnce(t, shape, consumeAllND(cs))
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_ND_Matches_Lower_Dimensions(t *testing.T) {
+	// with two dimensions, every order matches the XY orders
+	for _, order := range append(orders3DToTest, Serpentine, ColumnAsc, ColumnSerpentineDesc, SpiralIn, SpiralOut) {
+		want, err := makeCoordinateList(7, 5, order, 11)
+		require.NoError(t, err)
+		got, err := MakeCoordinateListND(CoordinateSupplierOptionsND{Shape: []int{7, 5}, Order: order, Seed: 11})
+		require.NoError(t, err)
+		require.Len(t, got, len(want))
+		for i := range want {
+			require.Equal(t, []int{want[i].X, want[i].Y}, got[i], OrderToString(order))
+		}
+	}
+
+	// with three dimensions, every order matches the XYZ orders
+	for _, order := range orders3DToTest {
+		want, err := MakeCoordinateList3D(CoordinateSupplierOptions3D{Width: 5, Height: 3, Depth: 4, Order: order, Seed: 11})
+		require.NoError(t, err)
+		got, err := MakeCoordinateListND(CoordinateSupplierOptionsND{Shape: []int{5, 3, 4}, Order: order, Seed: 11})
+		require.NoError(t, err)
+		for i := range want {
+			require.Equal(t, []int{want[i].X, want[i].Y, want[i].Z}, got[i], OrderToString(order))
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_ND_Hilbert_Adjacent(t *testing.T) {
+	for _, shape := range [][]int{{8}, {4, 4, 4, 4}, {2, 2, 2, 2, 2}} {
+		coords, err := MakeCoordinateListND(CoordinateSupplierOptionsND{Shape: shape, Order: Hilbert})
+		require.NoError(t, err)
+		for i := 1; i < len(coords); i++ {
+			distance := 0
+			for dimension := range shape {
+				distance += abs(coords[i][dimension] - coords[i-1][dimension])
+			}
+			require.Equal(t, 1, distance, "%v and %v are not adjacent", coords[i-1], coords[i])
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_ND_Repeat(t *testing.T) {
+	opts := CoordinateSupplierOptionsND{Shape: []int{3, 2, 2}, Order: RandomBijection, Seed: 5, Repeat: true}
+	cs, err := NewCoordinateSupplierND(opts)
+	require.NoError(t, err)
+	opts.Repeat = false
+	want, err := MakeCoordinateListND(opts)
+	require.NoError(t, err)
+	for pass := 0; pass < 3; pass++ {
+		for _, c := range want {
+			got, done := cs.Next()
+			require.False(t, done)
+			require.Equal(t, c, got)
+			// the caller owns the coordinate
+			got[0] = -1
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_ND_Concurrent(t *testing.T) {
+	shape := []int{10, 8, 6, 4}
+	cs, err := NewCoordinateSupplierND(CoordinateSupplierOptionsND{Shape: shape, Order: Random})
+	require.NoError(t, err)
+	var mu sync.Mutex
+	var coords [][]int
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got := consumeAllND(cs)
+			mu.Lock()
+			coords = append(coords, got...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	requireEachCoordinateNDOnce(t, shape, coords)
+}
+
+func Test_Coordinate_Supplier_ND_Invalid(t *testing.T) {
+	optsToTest := []CoordinateSupplierOptionsND{
+		{Order: Asc},
+		{Shape: []int{3, 0, 2}, Order: Asc},
+		{Shape: []int{3, -1}, Order: Asc},
+		{Shape: []int{1 << 20, 1 << 20}, Order: Asc},
+		{Shape: []int{2, 2, 2}, Order: SpiralIn},
+		{Shape: []int{2, 2}, Order: Order(1000)},
+	}
+	for _, opts := range optsToTest {
+		_, err := NewCoordinateSupplierND(opts)
+		require.Error(t, err, "%+v", opts)
+	}
+}
+
+// consumeAllND returns every coordinate handed out by a non-repeating CoordinateSupplierND.
+func consumeAllND(cs CoordinateSupplierND) [][]int {
+	var coords [][]int
+	for c, done := cs.Next(); !done; c, done = cs.Next() {
+		coords = append(coords, c)
+	}
+	return coords
+}
+
+// requireEachCoordinateNDOnce checks that coords holds every cell of the grid with the given shape exactly once.
+func requireEachCoordinateNDOnce(t testing.TB, shape []int, coords [][]int) {
+	cells := 1
+	for _, size := range shape {
+		cells *= size
+	}
+	require.Len(t, coords, cells)
+	seen := make(map[string]bool, len(coords))
+	for _, c := range coords {
+		require.Len(t, c, len(shape))
+		for dimension, size := range shape {
+			require.True(t, c[dimension] >= 0 && c[dimension] < size, "coordinate %v outside grid", c)
+		}
+		key := fmt.Sprint(c)
+		require.False(t, seen[key], "coordinate %v handed out twice", c)
+		seen[key] = true
+	}
+}
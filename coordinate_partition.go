@@ -0,0 +1,81 @@
+package coordinate_supplier
+
+import (
+	"fmt"
+	"math"
+)
+
+// Partitioning determines how a grid is split into partitions.
+type Partitioning uint
+
+const (
+	PartitionStrided    Partitioning = iota // partition i hands out the coordinates at indexes i, i+n, i+2n, ... of the Order
+	PartitionContiguous                     // partition i hands out the i-th contiguous range of indexes of the Order
+	PartitionTiles                          // partition i hands out the i-th rectangular tile of the grid, with the Order laid over the tile
+)
+
+// PartitionOptions select one of Count disjoint partitions of the grid.
+// The partitions of a grid together hand out every coordinate exactly once per pass.
+type PartitionOptions struct {
+	Count        int          // number of partitions, 0 hands out the whole grid
+	Index        int          // partition to hand out, from 0 to Count-1
+	Partitioning Partitioning // how the grid is split
+}
+
+// Partition splits the grid of opts into n partitions, and returns a CoordinateSupplier of the default implementation for each.
+// Together they hand out every coordinate exactly once per pass, each one in the Order of opts and repeating with Repeat.
+// Random orders are seeded once for all partitions, so strided and contiguous partitions split the same permutation.
+// PartitionTiles splits the grid into rows of tiles. If n does not split evenly into rows, some rows have one tile more than others.
+func Partition(opts CoordinateSupplierOptions, n int, partitioning Partitioning) ([]CoordinateSupplier, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("minimum partitions is 1")
+	}
+	if opts.random() {
+		opts = opts.withSeed()
+	}
+	suppliers := make([]CoordinateSupplier, n)
+	for i := range suppliers {
+		opts.Partition = PartitionOptions{Count: n, Index: i, Partitioning: partitioning}
+		cs, err := NewCoordinateSupplier(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed make partition %d: %w", i, err)
+		}
+		suppliers[i] = cs
+	}
+	return suppliers, nil
+}
+
+// tileLayout returns how many rows of tiles split a width x height grid into n tiles, choosing the layout with the most square tiles.
+// Every row is split into the same number of tiles if possible, otherwise the rows differ by one tile,
+// so any n up to the number of cells can be laid out.
+func tileLayout(width, height, n int) (rows int, ok bool) {
+	best := math.Inf(1)
+	even := false
+	for r := minInt(n, height); r >= 1; r-- {
+		columns := (n + r - 1) / r
+		if columns > width || (even && n%r != 0) {
+			continue
+		}
+		// distance of the tile aspect ratio from square
+		score := math.Abs(math.Log(float64(width) * float64(r) * float64(r) / (float64(n) * float64(height))))
+		if score < best || (!even && n%r == 0) {
+			best, rows, ok, even = score, r, true, n%r == 0
+		}
+	}
+	return rows, ok
+}
+
+// tileRow returns the row of tile i of n tiles laid out in rows, and the index of the first tile and number of tiles of that row.
+func tileRow(n, rows, i int) (row, first, columns int) {
+	for (row+1)*n/rows <= i {
+		row++
+	}
+	first, columns = splitRange(n, rows, row)
+	return row, first, columns
+}
+
+// splitRange splits [0, total) into parts ranges of nearly equal size, and returns the offset and size of range i.
+func splitRange(total, parts, i int) (offset, size int) {
+	offset = i * total / parts
+	return offset, (i+1)*total/parts - offset
+}
@@ -0,0 +1,158 @@
+package coordinate_supplier
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var partitioningsToTest = []Partitioning{PartitionStrided, PartitionContiguous, PartitionTiles}
+
+func TestPartition_EachCoordinateOnce(t *testing.T) {
+	for _, partitioning := range partitioningsToTest {
+		for _, order := range []Order{Asc, Serpentine, Hilbert, Random, RandomBijection} {
+			for _, n := range []int{1, 2, 3, 4, 6, 7} {
+				for _, supplier := range suppliersToTest {
+					t.Run(fmt.Sprintf("%s-%d-%s-%d", supplier.name, partitioning, OrderToString(order), n), func(t *testing.T) {
+						opts := CoordinateSupplierOptions{Width: 13, Height: 7, Order: order}
+						if opts.random() {
+							opts = opts.withSeed()
+						}
+						var coords []Coordinate
+						for i := 0; i < n; i++ {
+							opts.Partition = PartitionOptions{Count: n, Index: i, Partitioning: partitioning}
+							cs, err := supplier.new(opts)
+							require.NoError(t, err)
+							require.Equal(t, cs.(ProgressCoordinateSupplier).Len(), len(consumeAll(t, cs)))
+							cs.(SeekableCoordinateSupplier).Reset()
+							coords = append(coords, consumeAll(t, cs)...)
+						}
+						requireEachCoordinateOnce(t, 13, 7, coords)
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	for _, partitioning := range partitioningsToTest {
+		t.Run(fmt.Sprint(partitioning), func(t *testing.T) {
+			suppliers, err := Partition(CoordinateSupplierOptions{Width: 10, Height: 9, Order: Random}, 5, partitioning)
+			require.NoError(t, err)
+			require.Len(t, suppliers, 5)
+			var coords []Coordinate
+			for _, cs := range suppliers {
+				coords = append(coords, consumeAll(t, cs)...)
+			}
+			requireEachCoordinateOnce(t, 10, 9, coords)
+		})
+	}
+}
+
+func TestPartition_Order(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 4, Height: 2, Order: Asc}
+	want := map[Partitioning][][]Coordinate{
+		PartitionStrided: {
+			{{0, 0}, {2, 0}, {0, 1}, {2, 1}},
+			{{1, 0}, {3, 0}, {1, 1}, {3, 1}},
+		},
+		PartitionContiguous: {
+			{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+			{{0, 1}, {1, 1}, {2, 1}, {3, 1}},
+		},
+		PartitionTiles: {
+			{{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+			{{2, 0}, {3, 0}, {2, 1}, {3, 1}},
+		},
+	}
+	for partitioning, partitions := range want {
+		suppliers, err := Partition(opts, len(partitions), partitioning)
+		require.NoError(t, err)
+		for i, cs := range suppliers {
+			require.Equal(t, partitions[i], consumeAll(t, cs))
+		}
+	}
+}
+
+func TestPartition_Tiles_Uneven(t *testing.T) {
+	// the tiles of n that does not split into columns x rows fitting the grid
+	for _, size := range [][3]int{{5, 5, 7}, {5, 5, 25}, {3, 2, 5}, {13, 7, 11}, {1, 9, 9}, {9, 1, 9}} {
+		t.Run(fmt.Sprintf("%dx%d-%d", size[0], size[1], size[2]), func(t *testing.T) {
+			suppliers, err := Partition(CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: Asc}, size[2], PartitionTiles)
+			require.NoError(t, err)
+			var coords []Coordinate
+			for _, cs := range suppliers {
+				tile := consumeAll(t, cs)
+				require.NotEmpty(t, tile)
+				coords = append(coords, tile...)
+			}
+			requireEachCoordinateOnce(t, size[0], size[1], coords)
+		})
+	}
+
+	// 7 tiles on 5x5 are rows of 2, 2 and 3 tiles
+	suppliers, err := Partition(CoordinateSupplierOptions{Width: 5, Height: 5, Order: Asc}, 7, PartitionTiles)
+	require.NoError(t, err)
+	require.Equal(t, []Coordinate{{0, 0}, {1, 0}}, consumeAll(t, suppliers[0]))
+	require.Equal(t, []Coordinate{{3, 3}, {4, 3}, {3, 4}, {4, 4}}, consumeAll(t, suppliers[6]))
+}
+
+func TestPartition_Lazy(t *testing.T) {
+	for _, partitioning := range partitioningsToTest {
+		for _, order := range closedFormOrders {
+			opts := CoordinateSupplierOptions{Width: 11, Height: 6, Order: order, Seed: 5, Traversal: Traversal{ColumnMajor: true, ReverseX: true}}
+			for i := 0; i < 4; i++ {
+				opts.Partition = PartitionOptions{Count: 4, Index: i, Partitioning: partitioning}
+				want, err := MakeCoordinateListOptions(opts)
+				require.NoError(t, err)
+				cs, err := NewCoordinateSupplierLazy(opts)
+				require.NoError(t, err)
+				require.Equal(t, want, consumeAll(t, cs))
+			}
+		}
+	}
+}
+
+func TestPartition_Repeat(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 5, Height: 5, Order: RandomBijection, Repeat: true, Reshuffle: true, Seed: 9}
+	for _, partitioning := range partitioningsToTest {
+		for _, supplier := range suppliersToBenchmark {
+			// every pass of the partitions together hands out each coordinate once
+			suppliers := make([]CoordinateSupplier, 3)
+			for i := range suppliers {
+				opts.Partition = PartitionOptions{Count: 3, Index: i, Partitioning: partitioning}
+				cs, err := supplier.new(opts)
+				require.NoError(t, err)
+				suppliers[i] = cs
+			}
+			for pass := 0; pass < 3; pass++ {
+				var coords []Coordinate
+				for _, cs := range suppliers {
+					coords = append(coords, consumeN(t, cs, cs.(ProgressCoordinateSupplier).Len())...)
+				}
+				requireEachCoordinateOnce(t, 5, 5, coords)
+			}
+		}
+	}
+}
+
+func TestPartition_Invalid(t *testing.T) {
+	partitionsToTest := []PartitionOptions{
+		{Count: -1},
+		{Count: 2, Index: 2},
+		{Count: 2, Index: -1},
+		{Count: 7, Index: 0},
+		{Count: 7, Index: 0, Partitioning: PartitionTiles},
+		{Count: 2, Index: 0, Partitioning: 99},
+	}
+	for _, p := range partitionsToTest {
+		opts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc, Partition: p}
+		for _, supplier := range suppliersToBenchmark {
+			_, err := supplier.new(opts)
+			require.Error(t, err, "%s %+v", supplier.name, p)
+		}
+	}
+	_, err := Partition(CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc}, 0, PartitionStrided)
+	require.Error(t, err)
+}
@@ -0,0 +1,198 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// sequence hands out the items of a list with atomic.AddUint64, or the items computed from their index.
+// It is the machinery behind coordinateSupplierAtomic, coordinateSupplierLazy, and the suppliers of coordinates with more than two dimensions.
+type sequence[T any] struct {
+	items  []T              // items handed out on every pass, unless lookup is set
+	total  uint64           // number of items in one pass
+	lookup func(i uint64) T // returns the item at index i over all passes, when the items differ between passes or are not in a list
+	at     uint64
+	closed uint64
+	repeat bool
+}
+
+// next returns the next item to be handed out.
+// It may be possible to receive some items slightly out of order when called concurrently.
+func (s *sequence[T]) next() (item T, done bool) {
+	// check if already done
+	if s.exhausted() {
+		return item, true
+	}
+
+	// concurrent-safe and in-order get the next element index
+	atNow := atomic.AddUint64(&s.at, 1) - 1
+
+	// check if now done
+	if !s.repeat && atNow >= s.total {
+		return item, true
+	}
+
+	// return matching item (by now the timing may be slightly out-of-order)
+	return s.item(atNow), false
+}
+
+// nextN fills buf with the next items to be handed out, reserving all of them with one atomic.AddUint64.
+func (s *sequence[T]) nextN(buf []T) (n int, done bool) {
+	start, end, done := s.reserve(uint64(len(buf)))
+	if done {
+		return 0, true
+	}
+	for i := start; i < end; i++ {
+		buf[n] = s.item(i)
+		n++
+	}
+	return n, false
+}
+
+// nextContext returns the next item to be handed out, or closes the sequence if ctx is done.
+func (s *sequence[T]) nextContext(ctx context.Context) (item T, done bool) {
+	if ctx.Err() != nil {
+		s.close()
+		return item, true
+	}
+	return s.next()
+}
+
+// reserve reserves the indexes from start to end of the next n items, fewer than n only when the last items are handed out.
+func (s *sequence[T]) reserve(n uint64) (start, end uint64, done bool) {
+	// check if already done
+	if s.exhausted() {
+		return 0, 0, true
+	}
+	if n == 0 {
+		return 0, 0, false
+	}
+
+	// concurrent-safe and in-order reserve the next block of element indexes
+	end = atomic.AddUint64(&s.at, n)
+	start = end - n
+
+	// check if now done, or only part of the block is left
+	if !s.repeat {
+		total := s.total
+		if start >= total {
+			return 0, 0, true
+		}
+		if end > total {
+			end = total
+		}
+	}
+	return start, end, false
+}
+
+// item returns the item at index i of the handed out sequence, repeating past the end.
+func (s *sequence[T]) item(i uint64) T {
+	if s.lookup != nil {
+		return s.lookup(i)
+	}
+	// if repeating past the end, clamp to the current remainder position
+	return s.items[i%s.total]
+}
+
+// close marks the sequence as done for all callers.
+func (s *sequence[T]) close() {
+	atomic.StoreUint64(&s.closed, 1)
+}
+
+// exhausted reports whether the sequence was closed, or handed out all items without repeat.
+// The counter is checked before it is incremented, so it does not keep growing when called past the end.
+func (s *sequence[T]) exhausted() bool {
+	return atomic.LoadUint64(&s.closed) > 0 || (!s.repeat && atomic.LoadUint64(&s.at) >= s.total)
+}
+
+// handedOut returns the number of items handed out so far, over all passes.
+func (s *sequence[T]) handedOut() uint64 {
+	at := atomic.LoadUint64(&s.at)
+	// without repeat the counter keeps growing when called past the end
+	if !s.repeat && at > s.total {
+		return s.total
+	}
+	return at
+}
+
+// seek moves the sequence so the next item handed out is the one at index, and returns the index it moved to.
+func (s *sequence[T]) seek(index uint64) uint64 {
+	if !s.repeat && index > s.total {
+		index = s.total
+	}
+	atomic.StoreUint64(&s.at, index)
+	return index
+}
+
+// skip moves the sequence forward by n items.
+func (s *sequence[T]) skip(n uint64) {
+	atomic.AddUint64(&s.at, n)
+}
+
+// epochCache shares the items of the latest pass over the grid, and of the pass before it, between callers without locking.
+// Callers that are behind them build their own copy, callers that are ahead move the shared pass forward.
+// A pass is published before its items are built, so the callers reaching it at the same time build it only once.
+type epochCache[T any] struct {
+	build  func(epoch uint64) T
+	shared atomic.Pointer[epochPair[T]]
+}
+
+// epochPair are the passes shared by an epochCache.
+type epochPair[T any] struct {
+	latest   *epochItems[T]
+	previous *epochItems[T] // for callers still finishing the pass before latest
+}
+
+// epochItems are the items of one pass over the grid, built by the first caller that needs them.
+type epochItems[T any] struct {
+	epoch uint64
+	once  sync.Once
+	items T
+}
+
+// newEpochCache returns an epochCache building the items of a pass with build, sharing first as the items of the first pass.
+func newEpochCache[T any](first T, build func(epoch uint64) T) *epochCache[T] {
+	e := &epochCache[T]{build: build}
+	items := &epochItems[T]{items: first}
+	items.once.Do(func() {})
+	e.shared.Store(&epochPair[T]{latest: items})
+	return e
+}
+
+// get returns the items of the given pass.
+func (e *epochCache[T]) get(epoch uint64) T {
+	for {
+		shared := e.shared.Load()
+		if shared.latest.epoch == epoch {
+			return e.items(shared.latest)
+		}
+		if shared.previous != nil && shared.previous.epoch == epoch {
+			return e.items(shared.previous)
+		}
+		if epoch < shared.latest.epoch {
+			return e.build(epoch)
+		}
+		// only move the shared pass forward, another caller could have moved it past epoch since it was loaded
+		next := &epochItems[T]{epoch: epoch}
+		if e.shared.CompareAndSwap(shared, &epochPair[T]{latest: next, previous: shared.latest}) {
+			return e.items(next)
+		}
+	}
+}
+
+// seek shares the given pass, even if it is behind the latest one, for callers continuing from a new position.
+func (e *epochCache[T]) seek(epoch uint64) {
+	shared := e.shared.Load()
+	if shared.latest.epoch != epoch {
+		e.shared.Store(&epochPair[T]{latest: &epochItems[T]{epoch: epoch}, previous: shared.latest})
+	}
+}
+
+// items returns the items of pass, building them if no other caller did, or waiting for the caller building them.
+func (e *epochCache[T]) items(pass *epochItems[T]) T {
+	pass.once.Do(func() {
+		pass.items = e.build(pass.epoch)
+	})
+	return pass.items
+}
@@ -1,5 +1,10 @@
 package coordinate_supplier
 
+import (
+	"context"
+	"math/rand"
+)
+
 // CoordinateSupplier provides XY coordinates in a XY grid
 type CoordinateSupplier interface {
 	// Next should be called repeatedly to iterate through each pair of coordinates.
@@ -8,15 +13,109 @@ type CoordinateSupplier interface {
 	Next() (x, y int, done bool)
 }
 
+// BatchCoordinateSupplier is a CoordinateSupplier that can hand out many coordinates at once.
+// All CoordinateSupplier implementations of this package implement it, including the workers of NewCoordinateSupplierSharded
+// and the supplier returned by CompletionTracker.Filter.
+type BatchCoordinateSupplier interface {
+	CoordinateSupplier
+	// NextN fills buf with the next coordinates, reserving all of them with a single synchronization,
+	// and returns how many were filled. Fewer than len(buf) are filled only when the last coordinates are handed out.
+	// If done is true, no coordinates were filled and NextN should not be called any longer.
+	NextN(buf []Coordinate) (n int, done bool)
+}
+
+// ClosableCoordinateSupplier is a CoordinateSupplier that can be stopped before all coordinates are handed out,
+// which is the only way to stop a supplier that repeats.
+// All CoordinateSupplier implementations of this package implement it, including the workers of NewCoordinateSupplierSharded
+// and the supplier returned by CompletionTracker.Filter.
+type ClosableCoordinateSupplier interface {
+	CoordinateSupplier
+	// NextContext is like Next, but if ctx is done it closes the supplier and reports done.
+	NextContext(ctx context.Context) (x, y int, done bool)
+	// Close stops the supplier, every following call to Next, NextN or NextContext from any goroutine reports done.
+	// It always returns nil.
+	Close() error
+}
+
+// ProgressCoordinateSupplier is a CoordinateSupplier that reports how far it got, for progress bars and estimates.
+// The suppliers of NewCoordinateSupplier, NewCoordinateSupplierAtomic, NewCoordinateSupplierRWMutex, NewCoordinateSupplierOrdered
+// and NewCoordinateSupplierLazy implement it, and CompletionTracker.Filter when the filtered supplier does.
+// The workers of NewCoordinateSupplierSharded do not.
+type ProgressCoordinateSupplier interface {
+	CoordinateSupplier
+	// Len returns the number of coordinates in one pass over the grid.
+	Len() int
+	// HandedOut returns the number of coordinates handed out so far, over all passes.
+	HandedOut() uint64
+	// Remaining returns the number of coordinates left to hand out in the current pass.
+	Remaining() int
+	// Epoch returns the number of completed passes over the grid, which only grows past 1 with Repeat.
+	Epoch() uint64
+}
+
+// SeekableCoordinateSupplier is a CoordinateSupplier that can move to another position in its sequence of coordinates,
+// for example to restart a sweep or to resume after already processed coordinates.
+// It is safe to move while other goroutines call Next, they continue from the new position.
+// The suppliers of NewCoordinateSupplier, NewCoordinateSupplierAtomic, NewCoordinateSupplierRWMutex, NewCoordinateSupplierOrdered
+// and NewCoordinateSupplierLazy implement it. The workers of NewCoordinateSupplierSharded and CompletionTracker.Filter do not.
+type SeekableCoordinateSupplier interface {
+	CoordinateSupplier
+	// Reset rewinds the supplier to the first coordinate, as if it was just created. A closed supplier stays closed.
+	Reset()
+	// Seek moves the supplier so the next coordinate handed out is the one at index, counted over all passes like HandedOut.
+	Seek(index uint64)
+	// Skip moves the supplier forward by n coordinates without handing them out.
+	Skip(n uint64)
+}
+
 // CoordinateSupplierOptions control the way coordinates are handed out.
 type CoordinateSupplierOptions struct {
 	Width  int   // width of Coordinate grid
 	Height int   // height of Coordinate grid
-	Order  Order // order that coordinates will be handed out (Asc, Desc, Random)
+	MinX   int   // X of the leftmost column, X ranges from MinX to MinX+Width-1
+	MinY   int   // Y of the bottom row, Y ranges from MinY to MinY+Height-1
+	Order  Order // order that coordinates will be handed out (Asc, Desc, Random, Serpentine, Hilbert, ...)
 	Repeat bool  // if each Coordinate should be handed out exactly once, or if iterating should loop through indefinitely
+
+	Traversal Traversal // axis priority and direction along each axis applied to Order, the zero value leaves Order unchanged
+	Seed      int64     // seed for Random orders, the same seed always hands out the same permutation. Zero uses the global math/rand source
+	Reshuffle bool      // with Random orders and Repeat, hand out a new permutation on every pass instead of replaying the first one
+
+	Partition PartitionOptions // hand out only one of several disjoint parts of the grid, the zero value hands out the whole grid
+
+	StepX       int  // hand out only every StepX-th column, from MinX. Zero or one hands out every column
+	StepY       int  // hand out only every StepY-th row, from MinY. Zero or one hands out every row
+	Progressive bool // hand out the lattice of StepX and StepY first, then refine it with halved steps until every cell was handed out once. Steps must be powers of two
 }
 
 // NewCoordinateSupplier returns the default CoordinateSupplier implementation.
 func NewCoordinateSupplier(opts CoordinateSupplierOptions) (CoordinateSupplier, error) {
 	return NewCoordinateSupplierAtomic(opts)
 }
+
+// random reports whether the Order of the options is a random order, derived from Seed.
+func (opts CoordinateSupplierOptions) random() bool {
+	return opts.Order == Random || opts.Order == RandomBijection
+}
+
+// reshuffles reports whether a supplier created with the options hands out a new permutation on every pass.
+func (opts CoordinateSupplierOptions) reshuffles() bool {
+	return opts.Reshuffle && opts.Repeat && opts.random()
+}
+
+// withSeed returns the options with a seed drawn from the global math/rand source if none was given,
+// so that the permutation of every pass can be derived again from it, also when resuming from a Checkpoint.
+func (opts CoordinateSupplierOptions) withSeed() CoordinateSupplierOptions {
+	for opts.Seed == 0 {
+		opts.Seed = rand.Int63()
+	}
+	return opts
+}
+
+// remaining returns how many of total coordinates are left in the current pass after handedOut were handed out.
+func remaining(handedOut, total uint64, repeat bool) int {
+	if repeat {
+		return int(total - handedOut%total)
+	}
+	return int(total - handedOut)
+}
@@ -1,62 +1,138 @@
 package coordinate_supplier
 
 import (
+	"context"
 	"fmt"
 	"sync/atomic"
 )
 
 type coordinateSupplierAtomic struct {
-	coordinates []Coordinate
-	at          uint64
-	done        uint64
-	repeat      bool
-	order       Order
+	sequence  sequence[Coordinate] // coordinates of the first pass
+	opts      CoordinateSupplierOptions
+	reshuffle bool
+	shuffled  *epochCache[[]Coordinate] // coordinates of the latest passes when reshuffling
+	checkpointCodec
 }
 
 // NewCoordinateSupplierAtomic returns a CoordinateSupplier synchronized with atomic.AddUint64.
 // It is the fastest implementation but some coordinates could be received slightly out-of-order when called concurrently.
 func NewCoordinateSupplierAtomic(opts CoordinateSupplierOptions) (CoordinateSupplier, error) {
+	cs := &coordinateSupplierAtomic{}
+	if err := cs.init(opts); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// init sets up the supplier to hand out the coordinates of opts from the start.
+func (c *coordinateSupplierAtomic) init(opts CoordinateSupplierOptions) error {
 	if opts.Width < 1 {
-		return nil, fmt.Errorf("minimum width is 1")
+		return fmt.Errorf("minimum width is 1")
 	}
 	if opts.Height < 1 {
-		return nil, fmt.Errorf("minimum height is 1")
+		return fmt.Errorf("minimum height is 1")
 	}
-	coords, err := MakeCoordinateList(opts.Width, opts.Height, opts.Order)
+	if opts.random() {
+		opts = opts.withSeed()
+	}
+	coords, err := MakeCoordinateListOptions(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed make coordinate list: %w", err)
+		return fmt.Errorf("failed make coordinate list: %w", err)
 	}
 
-	cs := &coordinateSupplierAtomic{
-		repeat:      opts.Repeat,
-		coordinates: coords,
+	c.opts = opts
+	c.reshuffle = opts.reshuffles()
+	c.checkpointCodec = checkpointCodec{c}
+	c.sequence.items = coords
+	c.sequence.total = uint64(len(coords))
+	c.sequence.repeat = opts.Repeat
+	c.sequence.lookup = nil
+	if c.reshuffle {
+		c.shuffled = newEpochCache(coords, func(epoch uint64) []Coordinate {
+			return mustEpochCoordinates(opts, epoch)
+		})
+		total := uint64(len(coords))
+		c.sequence.lookup = func(i uint64) Coordinate {
+			return c.shuffled.get(i / total)[i%total]
+		}
 	}
-
-	return cs, nil
+	atomic.StoreUint64(&c.sequence.at, 0)
+	atomic.StoreUint64(&c.sequence.closed, 0)
+	return nil
 }
 
 // Next returns the next coordinate to be supplied.
 // It may be possible to receive some coordinates slightly out of order when called concurrently.
 func (c *coordinateSupplierAtomic) Next() (x, y int, done bool) {
-	// check if already done
-	if atomic.LoadUint64(&c.done) > 0 {
-		// already done
-		return 0, 0, true
-	}
+	coordinate, done := c.sequence.next()
+	return coordinate.X, coordinate.Y, done
+}
+
+// NextContext returns the next coordinate to be supplied, or closes the supplier if ctx is done.
+func (c *coordinateSupplierAtomic) NextContext(ctx context.Context) (x, y int, done bool) {
+	coordinate, done := c.sequence.nextContext(ctx)
+	return coordinate.X, coordinate.Y, done
+}
 
-	// concurrent-safe and in-order get the next element index
-	atNow := atomic.AddUint64(&c.at, 1) - 1
+// Close marks the supplier as done for all callers.
+func (c *coordinateSupplierAtomic) Close() error {
+	c.sequence.close()
+	return nil
+}
 
-	// check if now done
-	if !c.repeat && atNow >= uint64(len(c.coordinates)) {
-		// mark as done
-		atomic.AddUint64(&c.done, 1)
-		return 0, 0, true
+// Reset rewinds the supplier to the first coordinate.
+func (c *coordinateSupplierAtomic) Reset() {
+	c.Seek(0)
+}
+
+// Seek moves the supplier so the next coordinate handed out is the one at index.
+func (c *coordinateSupplierAtomic) Seek(index uint64) {
+	index = c.sequence.seek(index)
+	if c.reshuffle {
+		c.shuffled.seek(index / c.sequence.total)
 	}
+}
 
-	// if repeating past the end, clamp to the current remainder position
-	atNowClamped := atNow % uint64(len(c.coordinates))
+// Skip moves the supplier forward by n coordinates.
+func (c *coordinateSupplierAtomic) Skip(n uint64) {
+	c.sequence.skip(n)
+}
+
+// NextN fills buf with the next coordinates to be supplied, reserving all of them with one atomic.AddUint64.
+func (c *coordinateSupplierAtomic) NextN(buf []Coordinate) (n int, done bool) {
+	return c.sequence.nextN(buf)
+}
+
+// Len returns the number of coordinates in one pass over the grid.
+func (c *coordinateSupplierAtomic) Len() int {
+	return int(c.sequence.total)
+}
+
+// HandedOut returns the number of coordinates handed out so far, over all passes.
+func (c *coordinateSupplierAtomic) HandedOut() uint64 {
+	return c.sequence.handedOut()
+}
 
-	// return matching coordinate (by now the timing may be slightly out-of-order)
-	return c.coordinates[atNowClamped].X, c.coordinates[atNowClamped].Y, false
+// Remaining returns the number of coordinates left to hand out in the current pass.
+func (c *coordinateSupplierAtomic) Remaining() int {
+	return remaining(c.HandedOut(), uint64(c.Len()), c.sequence.repeat)
+}
+
+// Epoch returns the number of completed passes over the grid.
+func (c *coordinateSupplierAtomic) Epoch() uint64 {
+	return c.HandedOut() / uint64(c.Len())
+}
+
+// Checkpoint returns the current state of the supplier.
+func (c *coordinateSupplierAtomic) Checkpoint() Checkpoint {
+	return newCheckpoint(c.opts, c.HandedOut(), uint64(c.Len()))
+}
+
+// Restore replaces the state of the supplier with cp, to continue where the checkpointed supplier stopped.
+func (c *coordinateSupplierAtomic) Restore(cp Checkpoint) error {
+	if err := c.init(cp.Options); err != nil {
+		return err
+	}
+	c.Seek(cp.position(uint64(c.Len())))
+	return nil
 }
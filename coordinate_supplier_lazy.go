@@ -0,0 +1,144 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+type coordinateSupplierLazy struct {
+	sequence  sequence[Coordinate] // computes each coordinate from its index
+	opts      CoordinateSupplierOptions
+	reshuffle bool
+	shuffled  *epochCache[func(i int) Coordinate] // functions computing the coordinates of the latest passes when reshuffling
+	checkpointCodec
+}
+
+// NewCoordinateSupplierLazy returns a CoordinateSupplier synchronized with atomic.AddUint64, like NewCoordinateSupplierAtomic.
+// Instead of allocating the list of coordinates up front, each coordinate is computed from its index when handed out,
+// so memory use does not grow with the grid size. Only orders with a closed form are supported:
+// Asc, Desc, ColumnAsc, ColumnDesc, the serpentine orders and RandomBijection.
+func NewCoordinateSupplierLazy(opts CoordinateSupplierOptions) (CoordinateSupplier, error) {
+	cs := &coordinateSupplierLazy{}
+	if err := cs.init(opts); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// init sets up the supplier to hand out the coordinates of opts from the start.
+func (c *coordinateSupplierLazy) init(opts CoordinateSupplierOptions) error {
+	if opts.Width < 1 {
+		return fmt.Errorf("minimum width is 1")
+	}
+	if opts.Height < 1 {
+		return fmt.Errorf("minimum height is 1")
+	}
+	if opts.random() {
+		opts = opts.withSeed()
+	}
+	coordinateAt, err := coordinateFuncOptions(opts)
+	if err != nil {
+		return fmt.Errorf("failed make coordinate func: %w", err)
+	}
+
+	l, _ := opts.layout() // already validated by coordinateFuncOptions
+	total := uint64(l.count)
+	c.opts = opts
+	c.reshuffle = opts.reshuffles()
+	c.checkpointCodec = checkpointCodec{c}
+	c.sequence.total = total
+	c.sequence.repeat = opts.Repeat
+	c.sequence.lookup = func(i uint64) Coordinate {
+		return coordinateAt(int(i % total))
+	}
+	if c.reshuffle {
+		c.shuffled = newEpochCache(coordinateAt, func(epoch uint64) func(i int) Coordinate {
+			return mustEpochCoordinateFunc(opts, epoch)
+		})
+		c.sequence.lookup = func(i uint64) Coordinate {
+			return c.shuffled.get(i / total)(int(i % total))
+		}
+	}
+	atomic.StoreUint64(&c.sequence.at, 0)
+	atomic.StoreUint64(&c.sequence.closed, 0)
+	return nil
+}
+
+// Next returns the next coordinate to be supplied.
+// It may be possible to receive some coordinates slightly out of order when called concurrently.
+func (c *coordinateSupplierLazy) Next() (x, y int, done bool) {
+	coordinate, done := c.sequence.next()
+	return coordinate.X, coordinate.Y, done
+}
+
+// NextContext returns the next coordinate to be supplied, or closes the supplier if ctx is done.
+func (c *coordinateSupplierLazy) NextContext(ctx context.Context) (x, y int, done bool) {
+	coordinate, done := c.sequence.nextContext(ctx)
+	return coordinate.X, coordinate.Y, done
+}
+
+// Close marks the supplier as done for all callers.
+func (c *coordinateSupplierLazy) Close() error {
+	c.sequence.close()
+	return nil
+}
+
+// Reset rewinds the supplier to the first coordinate.
+func (c *coordinateSupplierLazy) Reset() {
+	c.Seek(0)
+}
+
+// Seek moves the supplier so the next coordinate handed out is the one at index.
+func (c *coordinateSupplierLazy) Seek(index uint64) {
+	index = c.sequence.seek(index)
+	if c.reshuffle {
+		c.shuffled.seek(index / c.sequence.total)
+	}
+}
+
+// Skip moves the supplier forward by n coordinates.
+func (c *coordinateSupplierLazy) Skip(n uint64) {
+	c.sequence.skip(n)
+}
+
+// NextN fills buf with the next coordinates to be supplied, reserving all of them with one atomic.AddUint64.
+func (c *coordinateSupplierLazy) NextN(buf []Coordinate) (n int, done bool) {
+	return c.sequence.nextN(buf)
+}
+
+// Len returns the number of coordinates in one pass over the grid.
+func (c *coordinateSupplierLazy) Len() int {
+	return int(c.sequence.total)
+}
+
+// HandedOut returns the number of coordinates handed out so far, over all passes.
+func (c *coordinateSupplierLazy) HandedOut() uint64 {
+	return c.sequence.handedOut()
+}
+
+// Remaining returns the number of coordinates left to hand out in the current pass.
+func (c *coordinateSupplierLazy) Remaining() int {
+	return remaining(c.HandedOut(), c.sequence.total, c.sequence.repeat)
+}
+
+// Epoch returns the number of completed passes over the grid.
+func (c *coordinateSupplierLazy) Epoch() uint64 {
+	return c.HandedOut() / c.sequence.total
+}
+
+// Checkpoint returns the current state of the supplier.
+func (c *coordinateSupplierLazy) Checkpoint() Checkpoint {
+	cp := newCheckpoint(c.opts, c.HandedOut(), uint64(c.Len()))
+	cp.Lazy = true
+	return cp
+}
+
+// Restore replaces the state of the supplier with cp, to continue where the checkpointed supplier stopped.
+func (c *coordinateSupplierLazy) Restore(cp Checkpoint) error {
+	if err := c.init(cp.Options); err != nil {
+		return err
+	}
+	c.Seek(cp.position(uint64(c.Len())))
+	return nil
+}
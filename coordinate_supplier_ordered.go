@@ -0,0 +1,108 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+)
+
+// coordinateSupplierOrdered serves the callers of a coordinateSupplierAtomic one at a time, in the order they took a ticket.
+type coordinateSupplierOrdered struct {
+	*coordinateSupplierAtomic
+	tickets         uint64 // tickets taken
+	served          uint64 // tickets served, the turn of the next caller
+	checkpointCodec        // restores the tickets as well
+}
+
+// NewCoordinateSupplierOrdered returns a CoordinateSupplier synchronized with a ticket turnstile instead of a mutex.
+// Like NewCoordinateSupplierRWMutex the coordinates are guaranteed to be handed out strictly in-order when used concurrently:
+// every caller takes a ticket with atomic.AddUint64, and waits until all callers with earlier tickets were served.
+// Waiting callers spin instead of sleeping, so it works best with not many more concurrent callers than GOMAXPROCS.
+func NewCoordinateSupplierOrdered(opts CoordinateSupplierOptions) (CoordinateSupplier, error) {
+	cs := &coordinateSupplierOrdered{coordinateSupplierAtomic: &coordinateSupplierAtomic{}}
+	cs.checkpointCodec = checkpointCodec{cs}
+	if err := cs.coordinateSupplierAtomic.init(opts); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// serve runs f once every caller with an earlier ticket was served, so no other caller runs at the same time.
+// It does not run f and reports false if the supplier is closed while waiting.
+func (c *coordinateSupplierOrdered) serve(f func()) bool {
+	ticket := atomic.AddUint64(&c.tickets, 1) - 1
+	for atomic.LoadUint64(&c.served) != ticket {
+		if atomic.LoadUint64(&c.sequence.closed) > 0 {
+			// the tickets are not served anymore
+			return false
+		}
+		runtime.Gosched()
+	}
+	f()
+	atomic.StoreUint64(&c.served, ticket+1)
+	return true
+}
+
+// Next returns the next coordinate to be supplied, strictly in order.
+func (c *coordinateSupplierOrdered) Next() (x, y int, done bool) {
+	// check if already done, without waiting for a turn
+	if c.sequence.exhausted() {
+		return 0, 0, true
+	}
+	done = true
+	c.serve(func() {
+		x, y, done = c.coordinateSupplierAtomic.Next()
+	})
+	return x, y, done
+}
+
+// NextN fills buf with the next coordinates to be supplied, strictly in order.
+func (c *coordinateSupplierOrdered) NextN(buf []Coordinate) (n int, done bool) {
+	if c.sequence.exhausted() || len(buf) == 0 {
+		return c.coordinateSupplierAtomic.NextN(buf)
+	}
+	done = true
+	c.serve(func() {
+		n, done = c.coordinateSupplierAtomic.NextN(buf)
+	})
+	return n, done
+}
+
+// NextContext returns the next coordinate to be supplied, or closes the supplier if ctx is done.
+func (c *coordinateSupplierOrdered) NextContext(ctx context.Context) (x, y int, done bool) {
+	if ctx.Err() != nil {
+		c.Close()
+		return 0, 0, true
+	}
+	return c.Next()
+}
+
+// Reset rewinds the supplier to the first coordinate, once the callers waiting before it were served.
+func (c *coordinateSupplierOrdered) Reset() {
+	c.Seek(0)
+}
+
+// Seek moves the supplier so the next coordinate handed out is the one at index, once the callers waiting before it were served.
+func (c *coordinateSupplierOrdered) Seek(index uint64) {
+	c.serve(func() {
+		c.coordinateSupplierAtomic.Seek(index)
+	})
+}
+
+// Skip moves the supplier forward by n coordinates, once the callers waiting before it were served.
+func (c *coordinateSupplierOrdered) Skip(n uint64) {
+	c.serve(func() {
+		c.coordinateSupplierAtomic.Skip(n)
+	})
+}
+
+// Restore replaces the state of the supplier with cp, to continue where the checkpointed supplier stopped.
+func (c *coordinateSupplierOrdered) Restore(cp Checkpoint) error {
+	if err := c.coordinateSupplierAtomic.Restore(cp); err != nil {
+		return err
+	}
+	// tickets left unserved when it was closed are abandoned
+	atomic.StoreUint64(&c.tickets, 0)
+	atomic.StoreUint64(&c.served, 0)
+	return nil
+}
@@ -1,6 +1,7 @@
 package coordinate_supplier
 
 import (
+	"context"
 	"fmt"
 	"sync"
 )
@@ -8,31 +9,52 @@ import (
 type coordinateSupplierRWMutex struct {
 	coordinates []Coordinate
 	at          int
+	epoch       uint64
+	closed      bool
 	repeat      bool
-	order       Order
+	opts        CoordinateSupplierOptions
+	reshuffle   bool
 	rw          sync.RWMutex
+	checkpointCodec
 }
 
 // NewCoordinateSupplierRWMutex returns a CoordinateSupplier synchronized with sync.RWMutex.
 // It blocks more and is slower than NewCoordinateSupplierAtomic, but the coordinates are guaranteed to be handed out strictly in-order when used concurrently.
 func NewCoordinateSupplierRWMutex(opts CoordinateSupplierOptions) (CoordinateSupplier, error) {
+	cs := &coordinateSupplierRWMutex{
+		rw: sync.RWMutex{},
+	}
+	if err := cs.init(opts); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// init sets up the supplier to hand out the coordinates of opts from the start, the caller must hold the lock.
+func (c *coordinateSupplierRWMutex) init(opts CoordinateSupplierOptions) error {
 	if opts.Width < 1 {
-		return nil, fmt.Errorf("minimum width is 1")
+		return fmt.Errorf("minimum width is 1")
 	}
 	if opts.Height < 1 {
-		return nil, fmt.Errorf("minimum height is 1")
+		return fmt.Errorf("minimum height is 1")
+	}
+	if opts.random() {
+		opts = opts.withSeed()
 	}
-	coords, err := MakeCoordinateList(opts.Width, opts.Height, opts.Order)
+	coords, err := MakeCoordinateListOptions(opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed make coordinate list: %w", err)
+		return fmt.Errorf("failed make coordinate list: %w", err)
 	}
 
-	cs := &coordinateSupplierRWMutex{
-		repeat:      opts.Repeat,
-		rw:          sync.RWMutex{},
-		coordinates: coords,
-	}
-	return cs, nil
+	c.repeat = opts.Repeat
+	c.opts = opts
+	c.reshuffle = opts.reshuffles()
+	c.coordinates = coords
+	c.checkpointCodec = checkpointCodec{c}
+	c.at = 0
+	c.epoch = 0
+	c.closed = false
+	return nil
 }
 
 // Next returns the next coordinate to be supplied.
@@ -40,9 +62,94 @@ func (c *coordinateSupplierRWMutex) Next() (x, y int, done bool) {
 	c.rw.Lock()
 	defer c.rw.Unlock()
 
+	return c.next()
+}
+
+// NextContext returns the next coordinate to be supplied, or closes the supplier if ctx is done.
+func (c *coordinateSupplierRWMutex) NextContext(ctx context.Context) (x, y int, done bool) {
+	if ctx.Err() != nil {
+		c.Close()
+		return 0, 0, true
+	}
+	return c.Next()
+}
+
+// Close marks the supplier as done for all callers.
+func (c *coordinateSupplierRWMutex) Close() error {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	c.closed = true
+	return nil
+}
+
+// Reset rewinds the supplier to the first coordinate.
+func (c *coordinateSupplierRWMutex) Reset() {
+	c.Seek(0)
+}
+
+// Seek moves the supplier so the next coordinate handed out is the one at index.
+func (c *coordinateSupplierRWMutex) Seek(index uint64) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	c.seek(index)
+}
+
+// Skip moves the supplier forward by n coordinates.
+func (c *coordinateSupplierRWMutex) Skip(n uint64) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	c.seek(c.handedOut() + n)
+}
+
+// seek moves the supplier so the next coordinate handed out is the one at index, the caller must hold the lock.
+func (c *coordinateSupplierRWMutex) seek(index uint64) {
+	total := uint64(len(c.coordinates))
+	if !c.repeat && index > total {
+		index = total
+	}
+	epoch, at := index/total, index%total
+	if at == 0 && epoch > 0 {
+		// stay at the end of the previous pass, like after handing out its last coordinate
+		epoch, at = epoch-1, total
+	}
+	if c.reshuffle && epoch != c.epoch {
+		c.coordinates = mustEpochCoordinates(c.opts, epoch)
+	}
+	c.epoch, c.at = epoch, int(at)
+}
+
+// NextN fills buf with the next coordinates to be supplied, locking once for all of them.
+func (c *coordinateSupplierRWMutex) NextN(buf []Coordinate) (n int, done bool) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	for n < len(buf) {
+		x, y, done := c.next()
+		if done {
+			return n, n == 0
+		}
+		buf[n] = Coordinate{X: x, Y: y}
+		n++
+	}
+	return n, false
+}
+
+// next returns the next coordinate to be supplied, the caller must hold the lock.
+func (c *coordinateSupplierRWMutex) next() (x, y int, done bool) {
+	if c.closed {
+		return 0, 0, true
+	}
+
 	if c.at >= len(c.coordinates) {
 		if c.repeat {
 			c.at = 0
+			c.epoch++
+			if c.reshuffle {
+				c.coordinates = mustEpochCoordinates(c.opts, c.epoch)
+			}
 		} else {
 			return 0, 0, true
 		}
@@ -51,3 +158,60 @@ func (c *coordinateSupplierRWMutex) Next() (x, y int, done bool) {
 	defer func() { c.at++ }()
 	return c.coordinates[c.at].X, c.coordinates[c.at].Y, false
 }
+
+// Len returns the number of coordinates in one pass over the grid.
+func (c *coordinateSupplierRWMutex) Len() int {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return len(c.coordinates)
+}
+
+// HandedOut returns the number of coordinates handed out so far, over all passes.
+func (c *coordinateSupplierRWMutex) HandedOut() uint64 {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return c.handedOut()
+}
+
+// Remaining returns the number of coordinates left to hand out in the current pass.
+func (c *coordinateSupplierRWMutex) Remaining() int {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return remaining(c.handedOut(), uint64(len(c.coordinates)), c.repeat)
+}
+
+// Epoch returns the number of completed passes over the grid.
+func (c *coordinateSupplierRWMutex) Epoch() uint64 {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return c.handedOut() / uint64(len(c.coordinates))
+}
+
+// handedOut returns the number of coordinates handed out so far, the caller must hold the lock.
+func (c *coordinateSupplierRWMutex) handedOut() uint64 {
+	return c.epoch*uint64(len(c.coordinates)) + uint64(c.at)
+}
+
+// Checkpoint returns the current state of the supplier.
+func (c *coordinateSupplierRWMutex) Checkpoint() Checkpoint {
+	c.rw.RLock()
+	defer c.rw.RUnlock()
+
+	return newCheckpoint(c.opts, c.handedOut(), uint64(len(c.coordinates)))
+}
+
+// Restore replaces the state of the supplier with cp, to continue where the checkpointed supplier stopped.
+func (c *coordinateSupplierRWMutex) Restore(cp Checkpoint) error {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	if err := c.init(cp.Options); err != nil {
+		return err
+	}
+	c.seek(cp.position(uint64(len(c.coordinates))))
+	return nil
+}
@@ -0,0 +1,204 @@
+package coordinate_supplier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// shardedCoordinates are the coordinates shared by the workers of NewCoordinateSupplierSharded.
+type shardedCoordinates struct {
+	source  *coordinateSupplierAtomic // only used to look up the coordinate at an index
+	shards  []shard
+	repeat  bool
+	total   uint64
+	passes  uint64 // next pass to hand out with Repeat, guarded by the locks of all shards
+	closed  uint64
+	drained uint64 // a worker found nothing to steal without Repeat, so the others stop looking
+}
+
+// shard is the range of indexes left to hand out by one worker.
+// lo and hi are only changed with mu locked, but can be loaded atomically to skip empty shards without locking.
+type shard struct {
+	mu     sync.Mutex
+	lo, hi uint64
+	_      [40]byte // keep shards on separate cache lines
+}
+
+// shardWorker is the CoordinateSupplier of one worker.
+type shardWorker struct {
+	s     *shardedCoordinates
+	index int
+}
+
+// NewCoordinateSupplierSharded returns one CoordinateSupplier for each of workers, which together hand out the coordinates of opts.
+// Each worker starts with its own contiguous range of the Order and hands it out without contending with the other workers.
+// A worker that ran out of coordinates steals half of the remaining range of another worker, so each coordinate is still handed out exactly once per pass.
+// The suppliers are safe for concurrent use, but are fastest when each one is only called by its own goroutine.
+// They implement BatchCoordinateSupplier and ClosableCoordinateSupplier, closing any of them closes all of them.
+func NewCoordinateSupplierSharded(opts CoordinateSupplierOptions, workers int) ([]CoordinateSupplier, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("minimum workers is 1")
+	}
+	source := &coordinateSupplierAtomic{}
+	if err := source.init(opts); err != nil {
+		return nil, err
+	}
+
+	s := &shardedCoordinates{
+		source: source,
+		shards: make([]shard, workers),
+		repeat: opts.Repeat,
+		total:  uint64(source.Len()),
+	}
+	s.fill()
+	suppliers := make([]CoordinateSupplier, workers)
+	for i := range suppliers {
+		suppliers[i] = &shardWorker{s: s, index: i}
+	}
+	return suppliers, nil
+}
+
+// Next returns the next coordinate to be supplied to the worker.
+func (w *shardWorker) Next() (x, y int, done bool) {
+	start, _, ok := w.s.take(w.index, 1)
+	if !ok {
+		return 0, 0, true
+	}
+	coordinate := w.s.source.sequence.item(start)
+	return coordinate.X, coordinate.Y, false
+}
+
+// NextN fills buf with the next coordinates to be supplied to the worker, taking all of them from one shard.
+func (w *shardWorker) NextN(buf []Coordinate) (n int, done bool) {
+	if len(buf) == 0 {
+		return 0, atomic.LoadUint64(&w.s.closed) > 0
+	}
+	start, end, ok := w.s.take(w.index, uint64(len(buf)))
+	if !ok {
+		return 0, true
+	}
+	for i := start; i < end; i++ {
+		buf[n] = w.s.source.sequence.item(i)
+		n++
+	}
+	return n, false
+}
+
+// NextContext returns the next coordinate to be supplied to the worker, or closes the suppliers of all workers if ctx is done.
+func (w *shardWorker) NextContext(ctx context.Context) (x, y int, done bool) {
+	if ctx.Err() != nil {
+		w.Close()
+		return 0, 0, true
+	}
+	return w.Next()
+}
+
+// Close marks the suppliers of all workers as done.
+func (w *shardWorker) Close() error {
+	atomic.StoreUint64(&w.s.closed, 1)
+	return nil
+}
+
+// take reserves up to n indexes for a worker, from its own shard or else stolen from another one.
+// Indexes are counted over all passes, ok is false when there are none left.
+func (s *shardedCoordinates) take(worker int, n uint64) (start, end uint64, ok bool) {
+	own := &s.shards[worker]
+	for atomic.LoadUint64(&s.closed) == 0 {
+		own.mu.Lock()
+		if own.lo < own.hi {
+			start, end = own.lo, own.hi
+			if end-start > n {
+				end = start + n
+			}
+			atomic.StoreUint64(&own.lo, end)
+			own.mu.Unlock()
+			return start, end, true
+		}
+		own.mu.Unlock()
+
+		if atomic.LoadUint64(&s.drained) == 0 && s.steal(worker) {
+			continue
+		}
+		if !s.repeat {
+			// ranges only move between shards without Repeat, so none are left to steal.
+			// A range moving past the search could be missed, but its worker still hands it out
+			atomic.StoreUint64(&s.drained, 1)
+			return 0, 0, false
+		}
+		s.refill()
+	}
+	return 0, 0, false
+}
+
+// steal moves the upper half of the range of another shard into the empty shard of worker.
+// It reports whether the shard of worker is not empty anymore.
+func (s *shardedCoordinates) steal(worker int) bool {
+	for k := 1; k < len(s.shards); k++ {
+		victim := (worker + k) % len(s.shards)
+		v := &s.shards[victim]
+		if atomic.LoadUint64(&v.lo) >= atomic.LoadUint64(&v.hi) {
+			continue
+		}
+
+		// lock in index order, so workers stealing from each other can not deadlock
+		own := &s.shards[worker]
+		first, second := own, v
+		if victim < worker {
+			first, second = v, own
+		}
+		first.mu.Lock()
+		second.mu.Lock()
+		if own.lo >= own.hi && v.lo < v.hi {
+			// round up, so the last coordinate of a shard can be stolen as well
+			mid := v.hi - (v.hi-v.lo+1)/2
+			atomic.StoreUint64(&own.lo, mid)
+			atomic.StoreUint64(&own.hi, v.hi)
+			atomic.StoreUint64(&v.hi, mid)
+		}
+		stolen := own.lo < own.hi
+		second.mu.Unlock()
+		first.mu.Unlock()
+		if stolen {
+			return true
+		}
+	}
+	return false
+}
+
+// refill starts the next passes over the grid once every shard is empty.
+func (s *shardedCoordinates) refill() {
+	for i := range s.shards {
+		s.shards[i].mu.Lock()
+	}
+	empty := true
+	for i := range s.shards {
+		if s.shards[i].lo < s.shards[i].hi {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		s.fill()
+	}
+	for i := range s.shards {
+		s.shards[i].mu.Unlock()
+	}
+}
+
+// fill splits the next passes over the grid between the shards, which must all be empty.
+// With Repeat several passes are handed out at once on small grids, so every shard gets enough coordinates to not refill constantly.
+func (s *shardedCoordinates) fill() {
+	passes := uint64(1)
+	if s.repeat {
+		passes = (uint64(len(s.shards))*64 + s.total - 1) / s.total
+	}
+	base, size := s.passes*s.total, passes*s.total
+	workers := uint64(len(s.shards))
+	for i := range s.shards {
+		atomic.StoreUint64(&s.shards[i].lo, base+uint64(i)*size/workers)
+		atomic.StoreUint64(&s.shards[i].hi, base+uint64(i+1)*size/workers)
+	}
+	s.passes += passes
+}
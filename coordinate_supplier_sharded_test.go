@@ -0,0 +1,157 @@
+package coordinate_supplier
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Coordinate_Supplier_Sharded(t *testing.T) {
+	for _, order := range []Order{Asc, Hilbert, Random} {
+		for _, workers := range []int{1, 3, 8, 200} {
+			t.Run(fmt.Sprintf("%s-%dworkers", OrderToString(order), workers), func(t *testing.T) {
+				suppliers, err := NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 17, Height: 9, Order: order}, workers)
+				require.NoError(t, err)
+				require.Len(t, suppliers, workers)
+
+				got := make([][]Coordinate, workers)
+				wg := sync.WaitGroup{}
+				for i, cs := range suppliers {
+					wg.Add(1)
+					go func(i int, cs CoordinateSupplier) {
+						defer wg.Done()
+						got[i] = consumeAll(t, cs)
+					}(i, cs)
+				}
+				wg.Wait()
+
+				var coords []Coordinate
+				for _, c := range got {
+					coords = append(coords, c...)
+				}
+				requireEachCoordinateOnce(t, 17, 9, coords)
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Sharded_Steal(t *testing.T) {
+	// one worker hands out the whole grid by stealing from the others, which are never called
+	suppliers, err := NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 10, Height: 10, Order: Asc}, 4)
+	require.NoError(t, err)
+	coords := consumeAll(t, suppliers[2])
+	requireEachCoordinateOnce(t, 10, 10, coords)
+	// it starts with its own range of the order
+	require.Equal(t, Coordinate{X: 0, Y: 5}, coords[0])
+	for _, cs := range suppliers {
+		_, _, done := cs.Next()
+		require.True(t, done)
+	}
+}
+
+func Test_Coordinate_Supplier_Sharded_NextN(t *testing.T) {
+	suppliers, err := NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 31, Height: 7, Order: Serpentine}, 5)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var coords []Coordinate
+	wg := sync.WaitGroup{}
+	for _, cs := range suppliers {
+		wg.Add(1)
+		go func(bcs BatchCoordinateSupplier) {
+			defer wg.Done()
+			buf := make([]Coordinate, 6)
+			for n, done := bcs.NextN(buf); !done; n, done = bcs.NextN(buf) {
+				require.NotZero(t, n)
+				mu.Lock()
+				coords = append(coords, buf[:n]...)
+				mu.Unlock()
+			}
+		}(cs.(BatchCoordinateSupplier))
+	}
+	wg.Wait()
+	requireEachCoordinateOnce(t, 31, 7, coords)
+}
+
+func Test_Coordinate_Supplier_Sharded_Repeat(t *testing.T) {
+	suppliers, err := NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 10, Height: 10, Order: Random, Repeat: true}, 2)
+	require.NoError(t, err)
+
+	// the next passes are only started when the current ones are all handed out, so after 8 passes each coordinate was handed out 8 times
+	counts := make(map[Coordinate]int)
+	for i := 0; i < 800; i++ {
+		// call the first worker twice as often, so it steals from the second one
+		x, y, done := suppliers[i%3%2].Next()
+		require.False(t, done)
+		counts[Coordinate{x, y}]++
+	}
+	require.Len(t, counts, 100)
+	for c, count := range counts {
+		require.Equal(t, 8, count, "coordinate %v", c)
+	}
+}
+
+func Test_Coordinate_Supplier_Sharded_Close(t *testing.T) {
+	suppliers, err := NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 10, Height: 10, Order: Asc, Repeat: true}, 3)
+	require.NoError(t, err)
+	consumeN(t, suppliers[0], 10)
+	require.NoError(t, suppliers[1].(ClosableCoordinateSupplier).Close())
+	for _, cs := range suppliers {
+		_, _, done := cs.Next()
+		require.True(t, done)
+	}
+}
+
+func Test_Coordinate_Supplier_Sharded_Invalid(t *testing.T) {
+	_, err := NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 10, Height: 10, Order: Asc}, 0)
+	require.Error(t, err)
+	_, err = NewCoordinateSupplierSharded(CoordinateSupplierOptions{Width: 0, Height: 10, Order: Asc}, 2)
+	require.Error(t, err)
+}
+
+func BenchmarkCoordinateSuppliersSharded(b *testing.B) {
+	for _, size := range []int{30, 1000} {
+		for consumers := 1; consumers <= 1000; consumers *= 10 {
+			opts := CoordinateSupplierOptions{Width: size, Height: size, Order: Asc}
+			b.Run(fmt.Sprintf("atomic-%dw-%dh-%dconsumers", size, size, consumers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					cs, err := NewCoordinateSupplierAtomic(opts)
+					require.NoError(b, err)
+					suppliers := make([]CoordinateSupplier, consumers)
+					for c := range suppliers {
+						suppliers[c] = cs
+					}
+					require.Equal(b, uint64(size*size), runEachCoordinateSupplier(suppliers))
+				}
+			})
+			b.Run(fmt.Sprintf("sharded-%dw-%dh-%dconsumers", size, size, consumers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					suppliers, err := NewCoordinateSupplierSharded(opts, consumers)
+					require.NoError(b, err)
+					require.Equal(b, uint64(size*size), runEachCoordinateSupplier(suppliers))
+				}
+			})
+		}
+	}
+}
+
+// runEachCoordinateSupplier consumes every supplier in its own goroutine, counting locally so consumers share nothing but the suppliers.
+// Passing the same supplier several times runs several consumers on it.
+func runEachCoordinateSupplier(suppliers []CoordinateSupplier) (consumed uint64) {
+	wg := sync.WaitGroup{}
+	for _, cs := range suppliers {
+		wg.Add(1)
+		go func(cs CoordinateSupplier) {
+			defer wg.Done()
+			var n uint64
+			for _, _, done := cs.Next(); !done; _, _, done = cs.Next() {
+				n++
+			}
+			atomic.AddUint64(&consumed, n)
+		}(cs)
+	}
+	wg.Wait()
+	return
+}
@@ -1,23 +1,38 @@
 package coordinate_supplier
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
-var suppliersToTest = []struct {
+type supplierToTest struct {
 	name string
 	new  func(options CoordinateSupplierOptions) (CoordinateSupplier, error)
-}{
+}
+
+var suppliersToTest = []supplierToTest{
 	{"atomic", NewCoordinateSupplierAtomic},
 	{"rw", NewCoordinateSupplierRWMutex},
+	{"ordered", NewCoordinateSupplierOrdered},
 }
 
+// suppliersToBenchmark also includes the suppliers that only support some orders.
+var suppliersToBenchmark = append(suppliersToTest[:len(suppliersToTest):len(suppliersToTest)],
+	supplierToTest{"lazy", NewCoordinateSupplierLazy},
+)
+
+// closedFormOrders are the orders supported by NewCoordinateSupplierLazy.
+var closedFormOrders = []Order{Asc, Desc, Serpentine, SerpentineDesc, ColumnAsc, ColumnDesc, ColumnSerpentine, ColumnSerpentineDesc, RandomBijection}
+
 func Test_Coordinate_Supplier_Asc_10x1(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{10, 1, Asc, false}
+	testOpts := CoordinateSupplierOptions{Width: 10, Height: 1, Order: Asc, Repeat: false}
 	// test the ones behind CoordinateSupplier interface
 	for _, supplier := range suppliersToTest {
 		t.Run(supplier.name, func(t *testing.T) {
@@ -38,7 +53,7 @@ func Test_Coordinate_Supplier_Asc_10x1(t *testing.T) {
 }
 
 func Test_Coordinate_Supplier_Asc_1x10(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{1, 10, Asc, false}
+	testOpts := CoordinateSupplierOptions{Width: 1, Height: 10, Order: Asc, Repeat: false}
 	// test the ones behind CoordinateSupplier interface
 	for _, supplier := range suppliersToTest {
 		t.Run(supplier.name, func(t *testing.T) {
@@ -59,7 +74,7 @@ func Test_Coordinate_Supplier_Asc_1x10(t *testing.T) {
 }
 
 func Test_Coordinate_Supplier_Asc_2x2(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{2, 2, Asc, false}
+	testOpts := CoordinateSupplierOptions{Width: 2, Height: 2, Order: Asc, Repeat: false}
 	// test the ones behind CoordinateSupplier interface
 	for _, supplier := range suppliersToTest {
 		t.Run(supplier.name, func(t *testing.T) {
@@ -92,7 +107,7 @@ func Test_Coordinate_Supplier_Asc_2x2(t *testing.T) {
 }
 
 func Test_Coordinate_Supplier_Desc_2x2(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{2, 2, Desc, false}
+	testOpts := CoordinateSupplierOptions{Width: 2, Height: 2, Order: Desc, Repeat: false}
 	// test the ones behind CoordinateSupplier interface
 	for _, supplier := range suppliersToTest {
 		t.Run(supplier.name, func(t *testing.T) {
@@ -125,7 +140,7 @@ func Test_Coordinate_Supplier_Desc_2x2(t *testing.T) {
 }
 
 func Test_Coordinate_Supplier_Asc_3x2_Repeat(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{3, 2, Asc, true}
+	testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc, Repeat: true}
 	xPattern := []int{0, 1, 2, 0, 1, 2}
 	yPattern := []int{0, 0, 0, 1, 1, 1}
 	// test the ones behind CoordinateSupplier interface
@@ -144,7 +159,7 @@ func Test_Coordinate_Supplier_Asc_3x2_Repeat(t *testing.T) {
 }
 
 func Test_Coordinate_Supplier_Desc_3x2_Repeat(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{3, 2, Desc, true}
+	testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Desc, Repeat: true}
 	xPattern := []int{2, 1, 0, 2, 1, 0}
 	yPattern := []int{1, 1, 1, 0, 0, 0}
 	// test the ones behind CoordinateSupplier interface
@@ -163,7 +178,7 @@ func Test_Coordinate_Supplier_Desc_3x2_Repeat(t *testing.T) {
 }
 
 func Test_Coordinate_Supplier_Desc_2x2_Repeat(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{2, 2, Desc, true}
+	testOpts := CoordinateSupplierOptions{Width: 2, Height: 2, Order: Desc, Repeat: true}
 	xPattern := []int{1, 0, 1, 0}
 	yPattern := []int{1, 1, 0, 0}
 	// test the ones behind CoordinateSupplier interface
@@ -181,8 +196,466 @@ func Test_Coordinate_Supplier_Desc_2x2_Repeat(t *testing.T) {
 	}
 }
 
+func Test_Coordinate_Supplier_Column_3x2_Repeat(t *testing.T) {
+	patterns := []struct {
+		order    Order
+		xPattern []int
+		yPattern []int
+	}{
+		{ColumnAsc, []int{0, 0, 1, 1, 2, 2}, []int{0, 1, 0, 1, 0, 1}},
+		{ColumnDesc, []int{2, 2, 1, 1, 0, 0}, []int{1, 0, 1, 0, 1, 0}},
+	}
+	for _, p := range patterns {
+		testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: p.order, Repeat: true}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%s", supplier.name, OrderToString(p.order)), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				for seen := 0; seen < 1000; seen++ {
+					x, y, done := cs.Next()
+					require.False(t, done)
+					require.Equal(t, p.xPattern[seen%len(p.xPattern)], x)
+					require.Equal(t, p.yPattern[seen%len(p.yPattern)], y)
+				}
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Traversal_3x2(t *testing.T) {
+	traversals := []struct {
+		traversal Traversal
+		want      []Coordinate
+	}{
+		{Traversal{}, []Coordinate{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 1}, {2, 1}}},
+		{Traversal{ReverseX: true}, []Coordinate{{2, 0}, {1, 0}, {0, 0}, {2, 1}, {1, 1}, {0, 1}}},
+		{Traversal{ReverseY: true}, []Coordinate{{0, 1}, {1, 1}, {2, 1}, {0, 0}, {1, 0}, {2, 0}}},
+		{Traversal{ReverseX: true, ReverseY: true}, []Coordinate{{2, 1}, {1, 1}, {0, 1}, {2, 0}, {1, 0}, {0, 0}}},
+		{Traversal{ColumnMajor: true}, []Coordinate{{0, 0}, {0, 1}, {1, 0}, {1, 1}, {2, 0}, {2, 1}}},
+		{Traversal{ColumnMajor: true, ReverseX: true}, []Coordinate{{2, 0}, {2, 1}, {1, 0}, {1, 1}, {0, 0}, {0, 1}}},
+		{Traversal{ColumnMajor: true, ReverseY: true}, []Coordinate{{0, 1}, {0, 0}, {1, 1}, {1, 0}, {2, 1}, {2, 0}}},
+		{Traversal{ColumnMajor: true, ReverseX: true, ReverseY: true}, []Coordinate{{2, 1}, {2, 0}, {1, 1}, {1, 0}, {0, 1}, {0, 0}}},
+	}
+	for _, tt := range traversals {
+		testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc, Traversal: tt.traversal}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%+v", supplier.name, tt.traversal), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				require.Equal(t, tt.want, consumeAll(t, cs))
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Traversal_Orders(t *testing.T) {
+	for order := Asc; OrderToString(order) != ""; order++ {
+		testOpts := CoordinateSupplierOptions{Width: 7, Height: 4, Order: order, Traversal: Traversal{ColumnMajor: true, ReverseX: true}}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%s", supplier.name, OrderToString(order)), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, consumeAll(t, cs))
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Origin(t *testing.T) {
+	for _, order := range []Order{Asc, Serpentine, Hilbert, RandomBijection} {
+		for _, supplier := range suppliersToBenchmark {
+			if supplier.name == "lazy" && order == Hilbert {
+				continue
+			}
+			t.Run(fmt.Sprintf("%s-%s", supplier.name, OrderToString(order)), func(t *testing.T) {
+				opts := CoordinateSupplierOptions{Width: 10, Height: 4, Order: order, Seed: 3, Traversal: Traversal{ReverseX: true}}
+				want, err := MakeCoordinateListOptions(opts)
+				require.NoError(t, err)
+
+				// x in [-5, 5), y in [-2, 2)
+				opts.MinX, opts.MinY = -5, -2
+				cs, err := supplier.new(opts)
+				require.NoError(t, err)
+				got := consumeAll(t, cs)
+				require.Len(t, got, len(want))
+				for i := range want {
+					require.Equal(t, Coordinate{X: want[i].X - 5, Y: want[i].Y - 2}, got[i])
+				}
+
+				// partitions are moved along
+				opts.Partition = PartitionOptions{Count: 2, Index: 1, Partitioning: PartitionTiles}
+				cs, err = supplier.new(opts)
+				require.NoError(t, err)
+				for _, c := range consumeAll(t, cs) {
+					require.True(t, c.X >= 0 && c.X < 5 && c.Y >= -2 && c.Y < 2, "coordinate %v outside tile", c)
+				}
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Step(t *testing.T) {
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(CoordinateSupplierOptions{Width: 10, Height: 7, MinX: -1, Order: Asc, StepX: 4, StepY: 3})
+			require.NoError(t, err)
+			require.Equal(t, []Coordinate{
+				{-1, 0}, {3, 0}, {7, 0},
+				{-1, 3}, {3, 3}, {7, 3},
+				{-1, 6}, {3, 6}, {7, 6},
+			}, consumeAll(t, cs))
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Progressive(t *testing.T) {
+	for _, order := range []Order{Asc, Serpentine, Hilbert, Random, RandomBijection} {
+		for _, steps := range [][2]int{{1, 1}, {4, 4}, {8, 2}, {1, 16}} {
+			for _, supplier := range suppliersToBenchmark {
+				if supplier.name == "lazy" && (order == Hilbert || order == Random) {
+					continue
+				}
+				t.Run(fmt.Sprintf("%s-%s-%dx%d", supplier.name, OrderToString(order), steps[0], steps[1]), func(t *testing.T) {
+					cs, err := supplier.new(CoordinateSupplierOptions{Width: 13, Height: 9, Order: order, Seed: 2, StepX: steps[0], StepY: steps[1], Progressive: true})
+					require.NoError(t, err)
+					coords := consumeAll(t, cs)
+					requireEachCoordinateOnce(t, 13, 9, coords)
+
+					// after each refinement, the coordinates handed out so far are the lattice of the halved steps
+					for stepX, stepY := steps[0], steps[1]; ; stepX, stepY = maxInt(stepX/2, 1), maxInt(stepY/2, 1) {
+						lattice := ceilDiv(13, stepX) * ceilDiv(9, stepY)
+						for _, c := range coords[:lattice] {
+							require.True(t, c.X%stepX == 0 && c.Y%stepY == 0, "coordinate %v not on %dx%d lattice", c, stepX, stepY)
+						}
+						if stepX == 1 && stepY == 1 {
+							break
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Progressive_Partition(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 16, Height: 16, Order: Random, StepX: 8, StepY: 8, Progressive: true}
+	suppliers, err := Partition(opts, 3, PartitionStrided)
+	require.NoError(t, err)
+	var coords []Coordinate
+	for _, cs := range suppliers {
+		coords = append(coords, consumeAll(t, cs)...)
+	}
+	requireEachCoordinateOnce(t, 16, 16, coords)
+
+	_, err = Partition(opts, 4, PartitionTiles)
+	require.Error(t, err)
+}
+
+func Test_Coordinate_Supplier_Step_Invalid(t *testing.T) {
+	optsToTest := []CoordinateSupplierOptions{
+		{Width: 8, Height: 8, Order: Asc, StepX: -1},
+		{Width: 8, Height: 8, Order: Asc, StepY: -2},
+		{Width: 8, Height: 8, Order: Asc, StepX: 3, Progressive: true},
+		{Width: 8, Height: 8, Order: Asc, StepX: 2, StepY: 6, Progressive: true},
+	}
+	for _, opts := range optsToTest {
+		for _, supplier := range suppliersToBenchmark {
+			_, err := supplier.new(opts)
+			require.Error(t, err, "%s %+v", supplier.name, opts)
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Serpentine_3x2_Repeat(t *testing.T) {
+	patterns := []struct {
+		order    Order
+		xPattern []int
+		yPattern []int
+	}{
+		{Serpentine, []int{0, 1, 2, 2, 1, 0}, []int{0, 0, 0, 1, 1, 1}},
+		{SerpentineDesc, []int{0, 1, 2, 2, 1, 0}, []int{1, 1, 1, 0, 0, 0}},
+		{ColumnSerpentine, []int{0, 0, 1, 1, 2, 2}, []int{0, 1, 1, 0, 0, 1}},
+		{ColumnSerpentineDesc, []int{2, 2, 1, 1, 0, 0}, []int{1, 0, 0, 1, 1, 0}},
+	}
+	for _, p := range patterns {
+		testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: p.order, Repeat: true}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%s", supplier.name, OrderToString(p.order)), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				for seen := 0; seen < 1000; seen++ {
+					x, y, done := cs.Next()
+					require.False(t, done)
+					require.Equal(t, p.xPattern[seen%len(p.xPattern)], x)
+					require.Equal(t, p.yPattern[seen%len(p.yPattern)], y)
+				}
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Serpentine_Adjacent(t *testing.T) {
+	for _, order := range []Order{Serpentine, SerpentineDesc, ColumnSerpentine, ColumnSerpentineDesc} {
+		for _, size := range [][2]int{{1, 1}, {5, 3}, {3, 5}, {1, 10}, {10, 1}} {
+			testOpts := CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: order, Repeat: false}
+			// test the ones behind CoordinateSupplier interface
+			for _, supplier := range suppliersToTest {
+				t.Run(fmt.Sprintf("%s-%s-%dx%d", supplier.name, OrderToString(order), size[0], size[1]), func(t *testing.T) {
+					cs, err := supplier.new(testOpts)
+					require.NoError(t, err)
+
+					coords := consumeAll(t, cs)
+					requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, coords)
+					requireAdjacent(t, coords)
+				})
+			}
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Spiral_3x3(t *testing.T) {
+	spiralIn := []Coordinate{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1}, {1, 1}}
+	// test the ones behind CoordinateSupplier interface
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(CoordinateSupplierOptions{Width: 3, Height: 3, Order: SpiralIn, Repeat: false})
+			require.NoError(t, err)
+			require.Equal(t, spiralIn, consumeAll(t, cs))
+
+			cs, err = supplier.new(CoordinateSupplierOptions{Width: 3, Height: 3, Order: SpiralOut, Repeat: false})
+			require.NoError(t, err)
+			coords := consumeAll(t, cs)
+			reverseCoordinates(coords)
+			require.Equal(t, spiralIn, coords)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_SpiralOut_Reverses_SpiralIn(t *testing.T) {
+	// on square grids of odd and even sizes, SpiralOut is SpiralIn reversed
+	for size := 1; size <= 8; size++ {
+		spiralIn, err := MakeCoordinateList(size, size, SpiralIn)
+		require.NoError(t, err)
+		spiralOut, err := MakeCoordinateList(size, size, SpiralOut)
+		require.NoError(t, err)
+		reverseCoordinates(spiralOut)
+		require.Equal(t, spiralIn, spiralOut, "%dx%d", size, size)
+	}
+}
+
+func Test_Coordinate_Supplier_SpiralOut_Center(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {2, 2}, {4, 4}, {5, 5}, {6, 6}, {7, 3}, {3, 7}, {6, 3}, {1, 10}, {10, 1}, {10, 2}, {2, 9}, {6, 1}} {
+		testOpts := CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: SpiralOut, Repeat: false}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%dx%d", supplier.name, size[0], size[1]), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+
+				coords := consumeAll(t, cs)
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, coords)
+				if testOpts.Width == testOpts.Height {
+					requireAdjacent(t, coords)
+				}
+
+				// the first coordinate is the center of the grid, or next to it on even sizes
+				require.Equal(t, Coordinate{X: (testOpts.Width - 1) / 2, Y: testOpts.Height / 2}, coords[0])
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Hilbert_8x8(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 8, Height: 8, Order: Hilbert, Repeat: false}
+	// test the ones behind CoordinateSupplier interface
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+
+			coords := consumeAll(t, cs)
+			requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, coords)
+			requireAdjacent(t, coords)
+			require.Equal(t, Coordinate{0, 0}, coords[0])
+			require.Equal(t, Coordinate{7, 0}, coords[len(coords)-1])
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Hilbert_Clipped(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {5, 3}, {3, 5}, {7, 7}, {1, 100}, {100, 1}, {33, 17}} {
+		testOpts := CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: Hilbert, Repeat: false}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%dx%d", supplier.name, size[0], size[1]), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, consumeAll(t, cs))
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_ZOrder(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {4, 4}, {5, 3}, {3, 5}, {1, 100}, {300, 2}, {33, 17}} {
+		testOpts := CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: ZOrder, Repeat: false}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToTest {
+			t.Run(fmt.Sprintf("%s-%dx%d", supplier.name, size[0], size[1]), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+
+				coords := consumeAll(t, cs)
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, coords)
+				for i := 1; i < len(coords); i++ {
+					require.Less(t, mortonCode(coords[i-1]), mortonCode(coords[i]))
+				}
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Random_Seed(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 20, Order: Random, Seed: 42}
+	// test the ones behind CoordinateSupplier interface
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			first := consumeAll(t, cs)
+			requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, first)
+
+			// drawing from the global source does not change a seeded permutation
+			rand.Int()
+
+			cs, err = supplier.new(testOpts)
+			require.NoError(t, err)
+			require.Equal(t, first, consumeAll(t, cs))
+
+			otherOpts := testOpts
+			otherOpts.Seed = 43
+			cs, err = supplier.new(otherOpts)
+			require.NoError(t, err)
+			require.NotEqual(t, first, consumeAll(t, cs))
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Random_Reshuffle(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 8, Height: 5, Order: Random, Repeat: true, Seed: 42, Reshuffle: true}
+	cells := testOpts.Width * testOpts.Height
+	takeEpochs := func(cs CoordinateSupplier, epochs int) [][]Coordinate {
+		passes := make([][]Coordinate, epochs)
+		for i := range passes {
+			for j := 0; j < cells; j++ {
+				x, y, done := cs.Next()
+				require.False(t, done)
+				passes[i] = append(passes[i], Coordinate{x, y})
+			}
+		}
+		return passes
+	}
+
+	var want [][]Coordinate
+	// test the ones behind CoordinateSupplier interface
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			passes := takeEpochs(cs, 5)
+			for i, pass := range passes {
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, pass)
+				if i > 0 {
+					require.NotEqual(t, passes[i-1], pass)
+				}
+			}
+
+			// the first pass is the same permutation as without reshuffling
+			noReshuffleOpts := testOpts
+			noReshuffleOpts.Reshuffle = false
+			cs, err = supplier.new(noReshuffleOpts)
+			require.NoError(t, err)
+			require.Equal(t, passes[0], takeEpochs(cs, 1)[0])
+
+			// every implementation derives the same passes from the seed
+			if want == nil {
+				want = passes
+			}
+			require.Equal(t, want, passes)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Random_Reshuffle_Concurrent(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 30, Order: Random, Repeat: true, Reshuffle: true}
+	// test the ones behind CoordinateSupplier interface
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			consumed := runCoordinateSupplier(cs, 100, 100000)
+			require.Equal(t, uint64(100000), consumed)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Reshuffle_Epoch_Forward(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 5, Height: 4, Order: RandomBijection, Repeat: true, Reshuffle: true, Seed: 1}
+	cs, err := NewCoordinateSupplierAtomic(testOpts)
+	require.NoError(t, err)
+	acs := cs.(*coordinateSupplierAtomic)
+	first := acs.shuffled.get(1)
+	acs.shuffled.get(2)
+	// a caller still finishing an older epoch does not move the shared epoch back
+	require.Equal(t, &first[0], &acs.shuffled.get(1)[0])
+	acs.shuffled.get(0)
+	require.Equal(t, uint64(2), acs.shuffled.shared.Load().latest.epoch)
+
+	cs, err = NewCoordinateSupplierLazy(testOpts)
+	require.NoError(t, err)
+	lcs := cs.(*coordinateSupplierLazy)
+	lcs.shuffled.get(2)
+	lcs.shuffled.get(1)
+	require.Equal(t, uint64(2), lcs.shuffled.shared.Load().latest.epoch)
+}
+
+func Test_Coordinate_Supplier_Reshuffle_Epoch_Built_Once(t *testing.T) {
+	var built uint64
+	cache := newEpochCache([]int{0}, func(epoch uint64) []int {
+		atomic.AddUint64(&built, 1)
+		// building takes a while, so the other callers reach the pass before it is built
+		time.Sleep(time.Millisecond)
+		return []int{int(epoch)}
+	})
+
+	// all callers reaching the next pass at the same time share the items built by one of them
+	var wrong uint64
+	for epoch := uint64(1); epoch <= 10; epoch++ {
+		start := make(chan struct{})
+		wg := sync.WaitGroup{}
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				if cache.get(epoch)[0] != int(epoch) {
+					atomic.AddUint64(&wrong, 1)
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+	}
+	require.Zero(t, wrong)
+	require.Equal(t, uint64(10), built)
+}
+
 func Test_Coordinate_Supplier_Asc_1000x1000_Concurrent(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{1000, 1000, Asc, false}
+	testOpts := CoordinateSupplierOptions{Width: 1000, Height: 1000, Order: Asc, Repeat: false}
 	// test the ones behind CoordinateSupplier interface
 	for _, supplier := range suppliersToTest {
 		t.Run(supplier.name, func(t *testing.T) {
@@ -194,8 +667,680 @@ func Test_Coordinate_Supplier_Asc_1000x1000_Concurrent(t *testing.T) {
 	}
 }
 
+func Test_Coordinate_Supplier_Ordered_Concurrent(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 120, Height: 100, Order: Random, Seed: 7}
+	want, err := MakeCoordinateListOptions(testOpts)
+	require.NoError(t, err)
+	position := make(map[Coordinate]int, len(want))
+	for i, c := range want {
+		position[c] = i
+	}
+
+	// received is a coordinate with the sequence numbers taken right before and right after the call that handed it out
+	type received struct {
+		coordinate Coordinate
+		start, end uint64
+	}
+
+	for _, batch := range []int{1, 7} {
+		t.Run(fmt.Sprintf("batch%d", batch), func(t *testing.T) {
+			cs, err := NewCoordinateSupplierOrdered(testOpts)
+			require.NoError(t, err)
+			bcs := cs.(BatchCoordinateSupplier)
+
+			var sequence, consumed uint64
+			receivedBy := make([][]received, 20)
+			wg := sync.WaitGroup{}
+			for i := range receivedBy {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					buf := make([]Coordinate, batch)
+					for atomic.LoadUint64(&consumed) < 10000 {
+						start := atomic.AddUint64(&sequence, 1)
+						var n int
+						var done bool
+						if batch == 1 {
+							buf[0].X, buf[0].Y, done = bcs.Next()
+							n = 1
+						} else {
+							n, done = bcs.NextN(buf)
+						}
+						end := atomic.AddUint64(&sequence, 1)
+						if done {
+							return
+						}
+						for _, c := range buf[:n] {
+							receivedBy[i] = append(receivedBy[i], received{c, start, end})
+						}
+						atomic.AddUint64(&consumed, uint64(n))
+					}
+				}()
+			}
+			wg.Wait()
+
+			// strictly in order: a call that returned before another call started handed out an earlier coordinate
+			byPosition := make([]*received, atomic.LoadUint64(&consumed))
+			require.GreaterOrEqual(t, len(byPosition), 10000)
+			for _, rs := range receivedBy {
+				for i := range rs {
+					p, ok := position[rs[i].coordinate]
+					require.True(t, ok)
+					require.Less(t, p, len(byPosition))
+					require.Nil(t, byPosition[p], "coordinate %v handed out twice", rs[i].coordinate)
+					byPosition[p] = &rs[i]
+				}
+			}
+			laterEnd := uint64(math.MaxUint64)
+			for p := len(byPosition) - 1; p >= 0; p-- {
+				require.Less(t, byPosition[p].start, laterEnd, "coordinate %d handed out after a later one was returned", p)
+				laterEnd = min(laterEnd, byPosition[p].end)
+			}
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Lazy_Matches_List(t *testing.T) {
+	for _, order := range closedFormOrders {
+		for _, size := range [][2]int{{1, 1}, {3, 2}, {5, 4}, {1, 7}, {7, 1}} {
+			for traversal := 0; traversal < 8; traversal++ {
+				testOpts := CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: order, Seed: 42, Traversal: Traversal{
+					ColumnMajor: traversal&1 > 0,
+					ReverseX:    traversal&2 > 0,
+					ReverseY:    traversal&4 > 0,
+				}}
+				t.Run(fmt.Sprintf("%s-%dx%d-%+v", OrderToString(order), size[0], size[1], testOpts.Traversal), func(t *testing.T) {
+					want, err := MakeCoordinateListOptions(testOpts)
+					require.NoError(t, err)
+					cs, err := NewCoordinateSupplierLazy(testOpts)
+					require.NoError(t, err)
+					require.Equal(t, want, consumeAll(t, cs))
+				})
+			}
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Lazy_Repeat(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Desc, Repeat: true}
+	xPattern := []int{2, 1, 0, 2, 1, 0}
+	yPattern := []int{1, 1, 1, 0, 0, 0}
+	cs, err := NewCoordinateSupplierLazy(testOpts)
+	require.NoError(t, err)
+	for seen := 0; seen < 1000; seen++ {
+		x, y, done := cs.Next()
+		require.False(t, done)
+		require.Equal(t, xPattern[seen%len(xPattern)], x)
+		require.Equal(t, yPattern[seen%len(yPattern)], y)
+	}
+}
+
+func Test_Coordinate_Supplier_Lazy_Huge(t *testing.T) {
+	cs, err := NewCoordinateSupplierLazy(CoordinateSupplierOptions{Width: 100000, Height: 100000, Order: Desc})
+	require.NoError(t, err)
+	x, y, done := cs.Next()
+	require.False(t, done)
+	require.Equal(t, 99999, x)
+	require.Equal(t, 99999, y)
+}
+
+func Test_Coordinate_Supplier_RandomBijection(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {2, 1}, {7, 5}, {16, 16}, {100, 3}, {257, 129}} {
+		testOpts := CoordinateSupplierOptions{Width: size[0], Height: size[1], Order: RandomBijection, Seed: 42}
+		// test the ones behind CoordinateSupplier interface
+		for _, supplier := range suppliersToBenchmark {
+			t.Run(fmt.Sprintf("%s-%dx%d", supplier.name, size[0], size[1]), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				first := consumeAll(t, cs)
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, first)
+
+				cs, err = supplier.new(testOpts)
+				require.NoError(t, err)
+				require.Equal(t, first, consumeAll(t, cs))
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_RandomBijection_Reshuffle(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 9, Height: 7, Order: RandomBijection, Repeat: true, Seed: 42, Reshuffle: true}
+	var want []Coordinate
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			var passes []Coordinate
+			for i := 0; i < 3*testOpts.Width*testOpts.Height; i++ {
+				x, y, done := cs.Next()
+				require.False(t, done)
+				passes = append(passes, Coordinate{x, y})
+			}
+			cells := testOpts.Width * testOpts.Height
+			for i := 0; i < 3; i++ {
+				requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, passes[i*cells:(i+1)*cells])
+			}
+			require.NotEqual(t, passes[:cells], passes[cells:2*cells])
+
+			// every implementation derives the same passes from the seed
+			if want == nil {
+				want = passes
+			}
+			require.Equal(t, want, passes)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Lazy_RandomBijection_Huge(t *testing.T) {
+	cs, err := NewCoordinateSupplierLazy(CoordinateSupplierOptions{Width: 100000, Height: 100000, Order: RandomBijection})
+	require.NoError(t, err)
+	seen := make(map[Coordinate]bool)
+	for i := 0; i < 10000; i++ {
+		x, y, done := cs.Next()
+		require.False(t, done)
+		require.True(t, x >= 0 && x < 100000 && y >= 0 && y < 100000)
+		require.False(t, seen[Coordinate{x, y}])
+		seen[Coordinate{x, y}] = true
+	}
+}
+
+func Test_Coordinate_Supplier_Lazy_Concurrent(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 1000, Height: 1000, Order: Asc, Repeat: false}
+	cs, err := NewCoordinateSupplierLazy(testOpts)
+	require.NoError(t, err)
+	consumed := runCoordinateSupplier(cs, 10, 0)
+	require.Equal(t, uint64(testOpts.Width*testOpts.Height), consumed)
+}
+
+func Test_Coordinate_Supplier_Lazy_Unsupported_Order(t *testing.T) {
+	for _, order := range []Order{Random, Hilbert, ZOrder, SpiralIn, SpiralOut} {
+		_, err := NewCoordinateSupplierLazy(CoordinateSupplierOptions{Width: 10, Height: 10, Order: order})
+		require.Error(t, err)
+	}
+}
+
+func Test_Coordinate_Supplier_NextN(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 7, Height: 5, Order: Desc, Repeat: false}
+	for _, supplier := range suppliersToBenchmark {
+		for _, size := range []int{1, 3, 7, 35, 100} {
+			t.Run(fmt.Sprintf("%s-%d", supplier.name, size), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				want := consumeAll(t, cs)
+
+				cs, err = supplier.new(testOpts)
+				require.NoError(t, err)
+				bcs, ok := cs.(BatchCoordinateSupplier)
+				require.True(t, ok)
+
+				var got []Coordinate
+				buf := make([]Coordinate, size)
+				for n, done := bcs.NextN(buf); !done; n, done = bcs.NextN(buf) {
+					require.Greater(t, n, 0)
+					got = append(got, buf[:n]...)
+				}
+				require.Equal(t, want, got)
+
+				// still done past the end
+				n, done := bcs.NextN(buf)
+				require.Equal(t, 0, n)
+				require.True(t, done)
+				_, _, done = bcs.Next()
+				require.True(t, done)
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_NextN_Repeat(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc, Repeat: true}
+	xPattern := []int{0, 1, 2, 0, 1, 2}
+	yPattern := []int{0, 0, 0, 1, 1, 1}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			bcs := cs.(BatchCoordinateSupplier)
+
+			buf := make([]Coordinate, 4)
+			for seen := 0; seen < 1000; {
+				n, done := bcs.NextN(buf)
+				require.False(t, done)
+				require.Equal(t, len(buf), n)
+				for _, c := range buf {
+					require.Equal(t, xPattern[seen%len(xPattern)], c.X)
+					require.Equal(t, yPattern[seen%len(yPattern)], c.Y)
+					seen++
+				}
+			}
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_NextN_Concurrent(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 300, Height: 300, Order: Asc, Repeat: false}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			bcs := cs.(BatchCoordinateSupplier)
+
+			mu := sync.Mutex{}
+			var got []Coordinate
+			wg := sync.WaitGroup{}
+			for i := 0; i < 100; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					buf := make([]Coordinate, 17)
+					for n, done := bcs.NextN(buf); !done; n, done = bcs.NextN(buf) {
+						mu.Lock()
+						got = append(got, buf[:n]...)
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			requireEachCoordinateOnce(t, testOpts.Width, testOpts.Height, got)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Close(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 30, Order: Asc, Repeat: true}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			ccs, ok := cs.(ClosableCoordinateSupplier)
+			require.True(t, ok)
+
+			// consumers of a repeating supplier run until it is closed
+			var consumed uint64
+			wg := sync.WaitGroup{}
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for _, _, done := ccs.Next(); !done; _, _, done = ccs.Next() {
+						if atomic.AddUint64(&consumed, 1) == 10000 {
+							ccs.Close()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			require.GreaterOrEqual(t, atomic.LoadUint64(&consumed), uint64(10000))
+
+			_, _, done := ccs.Next()
+			require.True(t, done)
+			_, done = ccs.(BatchCoordinateSupplier).NextN(make([]Coordinate, 10))
+			require.True(t, done)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_NextContext(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 30, Order: Asc, Repeat: true}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			ccs := cs.(ClosableCoordinateSupplier)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			_, _, done := ccs.NextContext(ctx)
+			require.False(t, done)
+
+			// cancelling the context of one consumer stops the others too
+			cancel()
+			_, _, done = ccs.NextContext(ctx)
+			require.True(t, done)
+			_, _, done = ccs.NextContext(context.Background())
+			require.True(t, done)
+			_, _, done = ccs.Next()
+			require.True(t, done)
+		})
+	}
+}
+
+func Test_CoordinateChannel(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 20, Order: Desc, Repeat: false}
+	// a negative buffer size is an unbuffered channel
+	for _, bufferSize := range []int{-1, 0, 1, 100} {
+		for _, supplier := range suppliersToBenchmark {
+			t.Run(fmt.Sprintf("%s-buffer%d", supplier.name, bufferSize), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				want := consumeAll(t, cs)
+
+				cs, err = supplier.new(testOpts)
+				require.NoError(t, err)
+				var got []Coordinate
+				for c := range CoordinateChannel(context.Background(), cs, bufferSize) {
+					got = append(got, c)
+				}
+				require.Equal(t, want, got)
+			})
+		}
+	}
+}
+
+func Test_CoordinateChannel_Cancel(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 20, Order: Asc, Repeat: true}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			ch := CoordinateChannel(ctx, cs, 10)
+			for i := 0; i < 1000; i++ {
+				<-ch
+			}
+
+			// after cancelling, the goroutine exits and closes the channel
+			cancel()
+			for range ch {
+			}
+		})
+	}
+}
+
+func Test_CoordinateChannel_Cancelled(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 30, Height: 20, Order: Asc, Repeat: true}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+
+			// with ctx already cancelled, no coordinate is taken from the shared supplier
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			for range CoordinateChannel(ctx, cs, 10) {
+			}
+			x, y, done := cs.Next()
+			require.False(t, done)
+			require.Equal(t, Coordinate{0, 0}, Coordinate{x, y})
+		})
+	}
+}
+
+func Test_All(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 7, Height: 5, Order: Hilbert, Repeat: false}
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			want := consumeAll(t, cs)
+
+			cs, err = supplier.new(testOpts)
+			require.NoError(t, err)
+			var got []Coordinate
+			for c := range All(cs) {
+				got = append(got, c)
+			}
+			require.Equal(t, want, got)
+
+			cs, err = supplier.new(testOpts)
+			require.NoError(t, err)
+			for i, c := range Enumerate(cs) {
+				require.Equal(t, want[i], c)
+			}
+		})
+	}
+}
+
+func Test_All_Break(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc, Repeat: true}
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+
+			for i, c := range Enumerate(cs) {
+				require.Equal(t, i%3, c.X)
+				if i == 10 {
+					break
+				}
+			}
+
+			// the supplier continues where the loop stopped
+			x, y, done := cs.Next()
+			require.False(t, done)
+			require.Equal(t, 2, x)
+			require.Equal(t, 1, y)
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Progress(t *testing.T) {
+	for _, repeat := range []bool{false, true} {
+		testOpts := CoordinateSupplierOptions{Width: 3, Height: 2, Order: Asc, Repeat: repeat}
+		for _, supplier := range suppliersToBenchmark {
+			t.Run(fmt.Sprintf("%s-repeat%t", supplier.name, repeat), func(t *testing.T) {
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				pcs, ok := cs.(ProgressCoordinateSupplier)
+				require.True(t, ok)
+
+				require.Equal(t, 6, pcs.Len())
+				require.Equal(t, uint64(0), pcs.HandedOut())
+				require.Equal(t, 6, pcs.Remaining())
+				require.Equal(t, uint64(0), pcs.Epoch())
+
+				for i := 0; i < 4; i++ {
+					pcs.Next()
+				}
+				require.Equal(t, uint64(4), pcs.HandedOut())
+				require.Equal(t, 2, pcs.Remaining())
+				require.Equal(t, uint64(0), pcs.Epoch())
+
+				pcs.(BatchCoordinateSupplier).NextN(make([]Coordinate, 5))
+				if repeat {
+					require.Equal(t, uint64(9), pcs.HandedOut())
+					require.Equal(t, 3, pcs.Remaining())
+				} else {
+					require.Equal(t, uint64(6), pcs.HandedOut())
+					require.Equal(t, 0, pcs.Remaining())
+				}
+				require.Equal(t, uint64(1), pcs.Epoch())
+
+				// calls past the end are not counted
+				for i := 0; i < 10; i++ {
+					pcs.Next()
+				}
+				if repeat {
+					require.Equal(t, uint64(19), pcs.HandedOut())
+					require.Equal(t, 5, pcs.Remaining())
+					require.Equal(t, uint64(3), pcs.Epoch())
+				} else {
+					require.Equal(t, uint64(6), pcs.HandedOut())
+					require.Equal(t, 0, pcs.Remaining())
+					require.Equal(t, uint64(1), pcs.Epoch())
+				}
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Progress_Concurrent(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 6, Height: 5, Order: Random, Repeat: true, Reshuffle: true}
+	for _, supplier := range suppliersToTest {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			pcs := cs.(ProgressCoordinateSupplier)
+			ccs := cs.(CheckpointCoordinateSupplier)
+
+			// progress is read while passes are reshuffled
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					cs.Next()
+				}
+			}()
+			for i := 0; i < 100; i++ {
+				require.Equal(t, 30, pcs.Len())
+				require.Less(t, ccs.Checkpoint().Index, uint64(30))
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Seek(t *testing.T) {
+	optsToTest := []CoordinateSupplierOptions{
+		{Width: 4, Height: 3, Order: Desc, Repeat: false},
+		{Width: 4, Height: 3, Order: Asc, Repeat: true},
+		{Width: 4, Height: 3, Order: RandomBijection, Repeat: true, Seed: 42, Reshuffle: true},
+	}
+	for _, testOpts := range optsToTest {
+		for _, supplier := range suppliersToBenchmark {
+			t.Run(fmt.Sprintf("%s-%s-repeat%t", supplier.name, OrderToString(testOpts.Order), testOpts.Repeat), func(t *testing.T) {
+				// the whole sequence, 3 passes when repeating
+				cs, err := supplier.new(testOpts)
+				require.NoError(t, err)
+				var want []Coordinate
+				for x, y, done := cs.Next(); !done && len(want) < 36; x, y, done = cs.Next() {
+					want = append(want, Coordinate{x, y})
+				}
+
+				cs, err = supplier.new(testOpts)
+				require.NoError(t, err)
+				scs, ok := cs.(SeekableCoordinateSupplier)
+				require.True(t, ok)
+				pcs := cs.(ProgressCoordinateSupplier)
+				next := func() Coordinate {
+					x, y, done := scs.Next()
+					require.False(t, done)
+					return Coordinate{x, y}
+				}
+
+				for _, index := range []int{5, 0, 11, 3, len(want) - 1, 12, 24, 7} {
+					if index >= len(want) {
+						continue
+					}
+					scs.Seek(uint64(index))
+					require.Equal(t, uint64(index), pcs.HandedOut())
+					require.Equal(t, want[index], next())
+				}
+
+				scs.Seek(2)
+				scs.Skip(3)
+				require.Equal(t, want[5], next())
+
+				// reset after handing out everything starts over
+				for _, _, done := scs.Next(); !done && pcs.HandedOut() < uint64(len(want)); _, _, done = scs.Next() {
+				}
+				scs.Reset()
+				require.Equal(t, uint64(0), pcs.HandedOut())
+				require.Equal(t, want[:len(want)/3], consumeN(t, scs, len(want)/3))
+
+				// seeking past the end without repeat is done
+				if !testOpts.Repeat {
+					scs.Seek(100)
+					_, _, done := scs.Next()
+					require.True(t, done)
+					require.Equal(t, uint64(12), pcs.HandedOut())
+					scs.Seek(11)
+					require.Equal(t, want[11], next())
+				}
+
+				// a closed supplier stays closed
+				scs.(ClosableCoordinateSupplier).Close()
+				scs.Reset()
+				_, _, done := scs.Next()
+				require.True(t, done)
+			})
+		}
+	}
+}
+
+func Test_Coordinate_Supplier_Reset_Concurrent(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 100, Height: 100, Order: Asc, Repeat: false}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			scs := cs.(SeekableCoordinateSupplier)
+
+			// consumers started before the reset keep going after it
+			wg := sync.WaitGroup{}
+			var consumed uint64
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for _, _, done := scs.Next(); !done; _, _, done = scs.Next() {
+						if atomic.AddUint64(&consumed, 1) == 5000 {
+							scs.Reset()
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			require.GreaterOrEqual(t, consumed, uint64(15000))
+		})
+	}
+}
+
+func Test_Coordinate_Supplier_Capabilities(t *testing.T) {
+	testOpts := CoordinateSupplierOptions{Width: 4, Height: 4, Order: Asc}
+	for _, supplier := range suppliersToBenchmark {
+		t.Run(supplier.name, func(t *testing.T) {
+			cs, err := supplier.new(testOpts)
+			require.NoError(t, err)
+			require.Implements(t, (*BatchCoordinateSupplier)(nil), cs)
+			require.Implements(t, (*ClosableCoordinateSupplier)(nil), cs)
+			require.Implements(t, (*ProgressCoordinateSupplier)(nil), cs)
+			require.Implements(t, (*SeekableCoordinateSupplier)(nil), cs)
+			require.Implements(t, (*CheckpointCoordinateSupplier)(nil), cs)
+		})
+	}
+
+	t.Run("sharded", func(t *testing.T) {
+		workers, err := NewCoordinateSupplierSharded(testOpts, 2)
+		require.NoError(t, err)
+		require.Implements(t, (*BatchCoordinateSupplier)(nil), workers[0])
+		require.Implements(t, (*ClosableCoordinateSupplier)(nil), workers[0])
+		_, ok := workers[0].(ProgressCoordinateSupplier)
+		require.False(t, ok)
+		_, ok = workers[0].(SeekableCoordinateSupplier)
+		require.False(t, ok)
+		_, ok = workers[0].(CheckpointCoordinateSupplier)
+		require.False(t, ok)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		tracker, err := NewCompletionTracker(testOpts)
+		require.NoError(t, err)
+		cs, err := NewCoordinateSupplier(testOpts)
+		require.NoError(t, err)
+		filtered := tracker.Filter(cs)
+		require.Implements(t, (*BatchCoordinateSupplier)(nil), filtered)
+		require.Implements(t, (*ClosableCoordinateSupplier)(nil), filtered)
+		require.Implements(t, (*ProgressCoordinateSupplier)(nil), filtered)
+		_, ok := filtered.(SeekableCoordinateSupplier)
+		require.False(t, ok)
+		_, ok = filtered.(CheckpointCoordinateSupplier)
+		require.False(t, ok)
+
+		// only suppliers reporting progress are filtered into one that does
+		workers, err := NewCoordinateSupplierSharded(testOpts, 2)
+		require.NoError(t, err)
+		filtered = tracker.Filter(workers[0])
+		require.Implements(t, (*BatchCoordinateSupplier)(nil), filtered)
+		require.Implements(t, (*ClosableCoordinateSupplier)(nil), filtered)
+		_, ok = filtered.(ProgressCoordinateSupplier)
+		require.False(t, ok)
+	})
+}
+
 func Test_ConsumePastEnd(t *testing.T) {
-	testOpts := CoordinateSupplierOptions{100, 100, Asc, false}
+	testOpts := CoordinateSupplierOptions{Width: 100, Height: 100, Order: Asc, Repeat: false}
 	// test the ones behind CoordinateSupplier interface
 	for _, supplier := range suppliersToTest {
 		t.Run(supplier.name, func(t *testing.T) {
@@ -228,6 +1373,18 @@ func Test_Readme_Example(t *testing.T) {
 		require.NoError(t, err)
 	}
 
+	for c := range All(cs) {
+		fmt.Println("The next coordinate is", c.X, c.Y)
+	}
+}
+
+func Test_Readme_Example_Next(t *testing.T) {
+	opts := CoordinateSupplierOptions{Width: 10, Height: 10, Order: Asc, Repeat: false}
+	cs, err := NewCoordinateSupplier(opts)
+	if err != nil {
+		require.NoError(t, err)
+	}
+
 	for x, y, done := cs.Next(); !done; x, y, done = cs.Next() {
 		fmt.Println("The next coordinate is", x, y)
 	}
@@ -244,7 +1401,7 @@ func BenchmarkCoordinateSuppliers(b *testing.B) {
 			for consume := 1; consume <= upToConsumed; consume *= 1000000 {
 				for consumers := 1; consumers <= upToConsumers; consumers *= 10 {
 					// run CoordinateSuppliers
-					for _, supplier := range suppliersToTest {
+					for _, supplier := range suppliersToBenchmark {
 						b.Run(fmt.Sprintf("%s-%dw-%dh-%dconsumers-consume%d", supplier.name, width, height, consumers, consume), func(b *testing.B) {
 							for i := 0; i < b.N; i++ {
 								// special case if consume == 1, then consume all coordinates once
@@ -258,7 +1415,7 @@ func BenchmarkCoordinateSuppliers(b *testing.B) {
 									// instead of consuming once, will loop until upToConsumed
 									repeat = true
 								}
-								cs, err := supplier.new(CoordinateSupplierOptions{width, height, Asc, repeat})
+								cs, err := supplier.new(CoordinateSupplierOptions{Width: width, Height: height, Order: Asc, Repeat: repeat})
 								require.NoError(b, err)
 
 								count := runCoordinateSupplier(cs, consumers, uint64(useConsume))
@@ -276,6 +1433,89 @@ func BenchmarkCoordinateSuppliers(b *testing.B) {
 	}
 }
 
+// consumeAll returns every coordinate handed out by a non-repeating CoordinateSupplier.
+func consumeAll(t testing.TB, cs CoordinateSupplier) []Coordinate {
+	var coords []Coordinate
+	for x, y, done := cs.Next(); !done; x, y, done = cs.Next() {
+		coords = append(coords, Coordinate{x, y})
+	}
+	return coords
+}
+
+// consumeN returns the next n coordinates handed out by cs.
+func consumeN(t testing.TB, cs CoordinateSupplier, n int) []Coordinate {
+	coords := make([]Coordinate, 0, n)
+	for len(coords) < n {
+		x, y, done := cs.Next()
+		require.False(t, done)
+		coords = append(coords, Coordinate{x, y})
+	}
+	return coords
+}
+
+// requireEachCoordinateOnce checks that coords holds every cell of the width x height grid exactly once.
+func requireEachCoordinateOnce(t testing.TB, width, height int, coords []Coordinate) {
+	require.Len(t, coords, width*height)
+	seen := make(map[Coordinate]bool, len(coords))
+	for _, c := range coords {
+		require.True(t, c.X >= 0 && c.X < width && c.Y >= 0 && c.Y < height, "coordinate %v outside grid", c)
+		require.False(t, seen[c], "coordinate %v handed out twice", c)
+		seen[c] = true
+	}
+}
+
+// requireAdjacent checks that each pair of consecutive coords are neighbouring cells.
+func requireAdjacent(t testing.TB, coords []Coordinate) {
+	for i := 1; i < len(coords); i++ {
+		dx := coords[i].X - coords[i-1].X
+		dy := coords[i].Y - coords[i-1].Y
+		require.Equal(t, 1, dx*dx+dy*dy, "coordinates %v and %v are not adjacent", coords[i-1], coords[i])
+	}
+}
+
+// mortonCode interleaves the bits of X and Y, with X in the lowest bit.
+func mortonCode(c Coordinate) uint64 {
+	var code uint64
+	for bit := 0; bit < 32; bit++ {
+		code |= uint64(c.X>>bit&1) << (2 * bit)
+		code |= uint64(c.Y>>bit&1) << (2*bit + 1)
+	}
+	return code
+}
+
+func BenchmarkCoordinateSuppliersNextN(b *testing.B) {
+	consumers := 1000
+	consume := uint64(1000000)
+
+	for _, batch := range []int{1, 16, 256} {
+		for _, supplier := range suppliersToBenchmark {
+			b.Run(fmt.Sprintf("%s-30w-30h-%dconsumers-consume%d-batch%d", supplier.name, consumers, consume, batch), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					cs, err := supplier.new(CoordinateSupplierOptions{Width: 30, Height: 30, Order: Asc, Repeat: true})
+					require.NoError(b, err)
+					bcs := cs.(BatchCoordinateSupplier)
+
+					var requested uint64
+					wg := sync.WaitGroup{}
+					for c := 0; c < consumers; c++ {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							buf := make([]Coordinate, batch)
+							for n, done := bcs.NextN(buf); !done; n, done = bcs.NextN(buf) {
+								if atomic.AddUint64(&requested, uint64(n)) >= consume {
+									return
+								}
+							}
+						}()
+					}
+					wg.Wait()
+				}
+			})
+		}
+	}
+}
+
 func runCoordinateSupplier(cs CoordinateSupplier, numConsumers int, maxConsumed uint64) (consumed uint64) {
 	// run consumers to get all coordinates
 	wg := sync.WaitGroup{}
@@ -4,6 +4,12 @@ package coordinate_supplier
  - in ascending order: 1, 2, 3, ...
  - in descending order: 3, 2, 1, ...
  - in random order: 2, 1, 3, ...
+ - in random order computed by a keyed bijection, without shuffling a list of all coordinates
+ - in column order: like ascending and descending, but bottom-to-top and then left-to-right
+ - in serpentine order: rows alternate direction, so consecutive coordinates are always adjacent
+ - in spiral order: winding from the corner into the center, or from the center out to the edges
+ - along a Hilbert curve: neighbouring coordinates are handed out close together
+ - in Z-order: sorted by Morton code, the bits of X and Y interleaved
 Coordinate system origin can be imagined in the bottom left. When ascending order, elements will be handed out left-to-right and then bottom-to-top.
 These are the first 9 points handed out when in ascending order for a 3x3 grid:
 
@@ -23,6 +29,17 @@ const (
 	Asc Order = iota
 	Desc
 	Random
+	Hilbert
+	ZOrder
+	Serpentine           // rows bottom-to-top, row 0 left-to-right, row 1 right-to-left, ...
+	SerpentineDesc       // Serpentine reversed
+	ColumnSerpentine     // columns left-to-right, column 0 bottom-to-top, column 1 top-to-bottom, ...
+	ColumnSerpentineDesc // ColumnSerpentine reversed
+	SpiralIn             // rings from the bottom left corner, winding counter-clockwise to the center
+	SpiralOut            // rings from the center cell winding clockwise outwards, which is SpiralIn reversed on square grids
+	ColumnAsc            // columns left-to-right, each column bottom-to-top
+	ColumnDesc           // ColumnAsc reversed
+	RandomBijection      // random order from a seeded bijection over the coordinate indexes, supported by NewCoordinateSupplierLazy
 )
 
 func OrderToString(o Order) string {
@@ -33,7 +50,39 @@ func OrderToString(o Order) string {
 		return "Desc"
 	case Random:
 		return "Random"
+	case Hilbert:
+		return "Hilbert"
+	case ZOrder:
+		return "ZOrder"
+	case Serpentine:
+		return "Serpentine"
+	case SerpentineDesc:
+		return "SerpentineDesc"
+	case ColumnSerpentine:
+		return "ColumnSerpentine"
+	case ColumnSerpentineDesc:
+		return "ColumnSerpentineDesc"
+	case SpiralIn:
+		return "SpiralIn"
+	case SpiralOut:
+		return "SpiralOut"
+	case ColumnAsc:
+		return "ColumnAsc"
+	case ColumnDesc:
+		return "ColumnDesc"
+	case RandomBijection:
+		return "RandomBijection"
 	default:
 		return ""
 	}
 }
+
+// Traversal changes the axis priority and the direction along each axis of an Order.
+// Together with Asc it expresses all eight row/column and direction combinations, for example
+// Traversal{ColumnMajor: true, ReverseY: true} hands out columns left-to-right, each column top-to-bottom.
+// The zero value leaves the Order unchanged.
+type Traversal struct {
+	ColumnMajor bool // swap the axis priority of the Order, so a row-major Order becomes column-major
+	ReverseX    bool // mirror X, so left-to-right becomes right-to-left
+	ReverseY    bool // mirror Y, so bottom-to-top becomes top-to-bottom
+}
@@ -14,6 +14,17 @@ func TestOrderToString(t *testing.T) {
 		{Asc, "Asc"},
 		{Desc, "Desc"},
 		{Random, "Random"},
+		{Hilbert, "Hilbert"},
+		{ZOrder, "ZOrder"},
+		{Serpentine, "Serpentine"},
+		{SerpentineDesc, "SerpentineDesc"},
+		{ColumnSerpentine, "ColumnSerpentine"},
+		{ColumnSerpentineDesc, "ColumnSerpentineDesc"},
+		{SpiralIn, "SpiralIn"},
+		{SpiralOut, "SpiralOut"},
+		{ColumnAsc, "ColumnAsc"},
+		{ColumnDesc, "ColumnDesc"},
+		{RandomBijection, "RandomBijection"},
 	}
 	for _, tt := range tests {
 		t.Run(fmt.Sprintf("want-%s", tt.want), func(t *testing.T) {